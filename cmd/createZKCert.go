@@ -16,10 +16,8 @@
 package cmd
 
 import (
-	"crypto/rand"
+	"encoding/json"
 	"fmt"
-	"math"
-	"math/big"
 	"os"
 	"time"
 
@@ -124,13 +122,11 @@ func createZKCert(f *createZKCertFlags) error {
 		return fmt.Errorf("sign certificate: %w", err)
 	}
 
-	salt, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64)) // [0, MaxInt64)
+	randomSalt, err := zkcertificate.NewSaltFromRandom()
 	if err != nil {
 		return fmt.Errorf("generate random salt: %w", err)
 	}
 
-	randomSalt := salt.Int64() + 1 // [1, MaxInt64]
-
 	certificate, err := zkcertificate.New(
 		holderCommitment.CommitmentHash,
 		certificateContent,
@@ -153,32 +149,15 @@ func createZKCert(f *createZKCertFlags) error {
 }
 
 func readCertificateContent(filePath string, standard zkcertificate.Standard) (zkcertificate.Content, error) {
-	switch standard {
-	case zkcertificate.StandardKYC:
-		var inputs zkcertificate.KYCInputs
-		if err := decodeJSONFile(filePath, &inputs); err != nil {
-			return nil, fmt.Errorf("read kyc inputs: %w", err)
-		}
-
-		certificateContent, err := inputs.FFEncode()
-		if err != nil {
-			return nil, fmt.Errorf("encode inputs to finite field: %w", err)
-		}
-
-		return certificateContent, nil
-	case zkcertificate.StandardSimpleJSON:
-		var inputs zkcertificate.SimpleJSON
-		if err := decodeJSONFile(filePath, &inputs); err != nil {
-			return nil, fmt.Errorf("read simple json: %w", err)
-		}
-
-		certificateContent, err := inputs.FFEncode()
-		if err != nil {
-			return nil, fmt.Errorf("encode inputs to finite field: %w", err)
-		}
-
-		return certificateContent, nil
-	default:
-		return nil, fmt.Errorf("standard %q is not supported", standard)
+	var inputs json.RawMessage
+	if err := decodeJSONFile(filePath, &inputs); err != nil {
+		return nil, fmt.Errorf("read certificate inputs: %w", err)
 	}
+
+	certificateContent, err := zkcertificate.DefaultFFEncoderRegistry.Encode(standard, inputs)
+	if err != nil {
+		return nil, fmt.Errorf("encode inputs to finite field: %w", err)
+	}
+
+	return certificateContent, nil
 }
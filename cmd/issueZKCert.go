@@ -24,13 +24,11 @@ import (
 	"os"
 	"time"
 
-	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/holiman/uint256"
 	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/cobra"
 
@@ -124,7 +122,7 @@ func issueZKCert(f *issueZKCertFlags) error {
 		return fmt.Errorf("ensure provider is guardian: %w", err)
 	}
 
-	emptyLeafIndex, proof, err := findEmptyTreeLeaf(ctx, client, registryAddress, registry, f.firstBlock)
+	emptyLeafIndex, proof, err := findEmptyTreeLeaf(ctx, client, registryAddress, f.firstBlock)
 	if err != nil {
 		return fmt.Errorf("find empty tree leaf: %w", err)
 	}
@@ -222,10 +220,9 @@ func findEmptyTreeLeaf(
 	ctx context.Context,
 	client *ethclient.Client,
 	registryAddress common.Address,
-	recordRegistry RegistryEventParser,
 	registryFirstEventBlockNumber int64,
 ) (int, merkle.Proof, error) {
-	tree, err := buildMerkleTreeFromEvents(ctx, client, registryAddress, recordRegistry, registryFirstEventBlockNumber)
+	tree, err := buildMerkleTreeFromEvents(ctx, client, registryAddress, registryFirstEventBlockNumber)
 	if err != nil {
 		return 0, merkle.Proof{}, fmt.Errorf("build merkle tree from events: %w", err)
 	}
@@ -292,16 +289,6 @@ func buildAndSaveOutput[T any](
 	return nil
 }
 
-// TODO: Deployed contract doesn't emit these events, it emits an older version of them
-var (
-	signatureRecordAddition   = crypto.Keccak256Hash([]byte("zkCertificateAddition(bytes32,address,uint256)"))
-	signatureRecordRevocation = crypto.Keccak256Hash([]byte("zkCertificateRevocation(bytes32,address,uint256)"))
-)
-
-var blocksDistance = big.NewInt(10_000)
-
-var blockDistancePlusOne = new(big.Int).Add(blocksDistance, big.NewInt(1))
-
 func newProgressBar(max int64) *progressbar.ProgressBar {
 	return progressbar.NewOptions64(
 		max,
@@ -325,52 +312,27 @@ func buildMerkleTreeFromEvents(
 	ctx context.Context,
 	client *ethclient.Client,
 	registryAddress common.Address,
-	registryEventParser RegistryEventParser,
 	firstBlock int64,
 ) (*merkle.Tree, error) {
-	tree, err := merkle.NewEmptyTree(merkle.TreeDepth, merkle.EmptyLeafValue)
-	if err != nil {
-		return nil, fmt.Errorf("initialize empty tree: %w", err)
-	}
-
 	head, err := client.BlockNumber(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("retrieve head block number: %w", err)
 	}
 
-	headBlock := big.NewInt(int64(head))
-
-	bar := newProgressBar(int64(head) - firstBlock)
-
-	for i := big.NewInt(firstBlock); i.Cmp(headBlock) == -1; i.Add(i, blockDistancePlusOne) {
-		fromBlock := new(big.Int).Set(i)
-		toBlock := new(big.Int).Add(fromBlock, blocksDistance)
-		if toBlock.Cmp(headBlock) == 1 {
-			toBlock = new(big.Int).Set(headBlock)
-		}
+	bar := newProgressBar(int64(head) - firstBlock + 1)
 
-		logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
-			FromBlock: fromBlock,
-			ToBlock:   toBlock,
-			Addresses: []common.Address{registryAddress},
-			Topics:    [][]common.Hash{{signatureRecordAddition, signatureRecordRevocation}},
-		})
-		if err != nil {
-			return nil, fmt.Errorf("execute filter query: %w", err)
-		}
-
-		for _, logEntry := range logs {
-			if err := processEvent(logEntry, registryEventParser, tree); err != nil {
-				return nil, err
-			}
-		}
-
-		blockRangeLength := new(big.Int).Sub(toBlock, fromBlock).Int64()
-		if toBlock.Cmp(headBlock) != 0 {
-			blockRangeLength++ // because it is a closed range
-		}
-
-		_ = bar.Add64(blockRangeLength)
+	tree, err := merkle.NewTreeFromEthereumEvents(
+		ctx,
+		client,
+		registryAddress,
+		uint64(firstBlock),
+		head,
+		merkle.WithReplayProgress(func(blocks uint64) {
+			_ = bar.Add64(int64(blocks))
+		}),
+	)
+	if err != nil {
+		return nil, err
 	}
 
 	_ = bar.Finish()
@@ -378,51 +340,14 @@ func buildMerkleTreeFromEvents(
 	return tree, nil
 }
 
-func processEvent(logEntry types.Log, registryEventParser RegistryEventParser, tree *merkle.Tree) error {
-	if logEntry.Removed {
-		return fmt.Errorf("not supported: log is removed due to chain reorganisation")
-	}
-
-	var index int
-	var leafHash *uint256.Int
-
-	switch logEntry.Topics[0] {
-	case signatureRecordAddition:
-		eventAddition, err := registryEventParser.ParseZkCertificateAddition(logEntry)
-		if err != nil {
-			return fmt.Errorf("parse addition record event: %w", err)
-		}
-
-		index = int(eventAddition.Index.Int64())
-
-		var isOverflow bool
-		leafHash, isOverflow = uint256.FromBig(new(big.Int).SetBytes(eventAddition.ZkCertificateLeafHash[:]))
-		if isOverflow {
-			return fmt.Errorf("invalid leaf hash")
-		}
-	case signatureRecordRevocation:
-		eventRevocation, err := registryEventParser.ParseZkCertificateRevocation(logEntry)
+func findFirstEmptyLeafIndex(tree *merkle.Tree) (int, error) {
+	for i := 0; i < tree.GetLeavesAmount(); i++ {
+		leaf, err := tree.GetLeaf(i)
 		if err != nil {
-			return fmt.Errorf("parse revocation record event: %w", err)
+			return 0, fmt.Errorf("get leaf: %w", err)
 		}
 
-		index = int(eventRevocation.Index.Int64())
-		leafHash = merkle.EmptyLeafValue
-	}
-
-	if err := tree.SetLeaf(index, merkle.TreeNode{Value: leafHash}); err != nil {
-		return fmt.Errorf("set leaf: %w", err)
-	}
-
-	return nil
-}
-
-func findFirstEmptyLeafIndex(tree *merkle.Tree) (int, error) {
-	leavesAmount := tree.GetLeavesAmount()
-	offset := len(tree.Nodes) - leavesAmount
-
-	for i := 0; i < leavesAmount; i++ {
-		if tree.Nodes[offset+i].Value.Eq(merkle.EmptyLeafValue) {
+		if leaf.IsEmpty() {
 			return i, nil
 		}
 	}
@@ -16,11 +16,8 @@
 package cmd
 
 import (
-	"crypto/rand"
 	"encoding/json"
 	"fmt"
-	"math"
-	"math/big"
 	"os"
 	"time"
 
@@ -110,13 +107,11 @@ func renewZKCert(f *renewZKCertFlags) error {
 		return fmt.Errorf("sign certificate: %w", err)
 	}
 
-	salt, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64)) // [0, MaxInt64)
+	randomSalt, err := zkcertificate.NewSaltFromRandom()
 	if err != nil {
 		return fmt.Errorf("generate random salt: %w", err)
 	}
 
-	randomSalt := salt.Int64() + 1 // [1, MaxInt64]
-
 	newCertificate, err := zkcertificate.New(
 		certificate.HolderCommitment,
 		certificateContent,
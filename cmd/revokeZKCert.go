@@ -119,7 +119,7 @@ func revokeZKCert(f *revokeZKCertFlags) error {
 	leafIndex := certificate.Registration.LeafIndex
 	leafHash := certificate.LeafHash
 
-	proof, err := buildMerkleProof(ctx, client, registryAddress, registry, leafIndex, leafHash, f.firstBlock)
+	proof, err := buildMerkleProof(ctx, client, registryAddress, leafIndex, leafHash, f.firstBlock)
 	if err != nil {
 		return fmt.Errorf("build merkle proof: %w", err)
 	}
@@ -173,12 +173,11 @@ func buildMerkleProof(
 	ctx context.Context,
 	client *ethclient.Client,
 	registryAddress common.Address,
-	registry RegistryEventParser,
 	leafIndex int,
 	leafHash zkcertificate.Hash,
 	firstBlock int64,
 ) (merkle.Proof, error) {
-	tree, err := buildMerkleTreeFromEvents(ctx, client, registryAddress, registry, firstBlock)
+	tree, err := buildMerkleTreeFromEvents(ctx, client, registryAddress, firstBlock)
 	if err != nil {
 		return merkle.Proof{}, fmt.Errorf("build merkle tree from events: %w", err)
 	}
@@ -0,0 +1,119 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package keymanagement
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/babyjub"
+)
+
+// BabyJubKeyPair holds a Baby Jubjub private key together with its public key.
+type BabyJubKeyPair struct {
+	PrivateKey babyjub.PrivateKey
+	PublicKey  *babyjub.PublicKey
+}
+
+type babyJubKeyPairJSON struct {
+	PrivateKey string `json:"privateKey"`
+	PublicKey  string `json:"publicKey"`
+}
+
+// Generate creates a new key pair from a random private key read from crypto/rand.
+func Generate() (*BabyJubKeyPair, error) {
+	var privateKey babyjub.PrivateKey
+	if _, err := io.ReadFull(rand.Reader, privateKey[:]); err != nil {
+		return nil, fmt.Errorf("read random bytes: %w", err)
+	}
+
+	return NewBabyJubKeyPair(privateKey), nil
+}
+
+// NewBabyJubKeyPair creates a key pair from the given private key.
+func NewBabyJubKeyPair(privateKey babyjub.PrivateKey) *BabyJubKeyPair {
+	return &BabyJubKeyPair{
+		PrivateKey: privateKey,
+		PublicKey:  privateKey.Public(),
+	}
+}
+
+// Sign signs the message with the private key using the Poseidon hash.
+func (k *BabyJubKeyPair) Sign(message *big.Int) *babyjub.Signature {
+	return k.PrivateKey.SignPoseidon(message)
+}
+
+// Verify checks the Poseidon signature of the message against the public key.
+func (k *BabyJubKeyPair) Verify(message *big.Int, signature *babyjub.Signature) bool {
+	return k.PublicKey.VerifyPoseidon(message, signature)
+}
+
+// MarshalJSON implements [json.Marshaler]. The private key is encoded as a hex string,
+// and the public key as a hex-encoded compressed point.
+func (k BabyJubKeyPair) MarshalJSON() ([]byte, error) {
+	if k.PublicKey == nil {
+		return nil, fmt.Errorf("public key is missing")
+	}
+
+	publicKey := k.PublicKey.Compress()
+
+	return json.Marshal(babyJubKeyPairJSON{
+		PrivateKey: hex.EncodeToString(k.PrivateKey[:]),
+		PublicKey:  hex.EncodeToString(publicKey[:]),
+	})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. The public key must correspond to the private key.
+func (k *BabyJubKeyPair) UnmarshalJSON(data []byte) error {
+	var keyPair babyJubKeyPairJSON
+	if err := json.Unmarshal(data, &keyPair); err != nil {
+		return err
+	}
+
+	privateKeyBytes, err := hex.DecodeString(keyPair.PrivateKey)
+	if err != nil {
+		return fmt.Errorf("decode private key: %w", err)
+	}
+
+	var privateKey babyjub.PrivateKey
+	if len(privateKeyBytes) != len(privateKey) {
+		return fmt.Errorf("invalid private key length %d, want %d", len(privateKeyBytes), len(privateKey))
+	}
+	copy(privateKey[:], privateKeyBytes)
+
+	publicKeyBytes, err := hex.DecodeString(keyPair.PublicKey)
+	if err != nil {
+		return fmt.Errorf("decode public key: %w", err)
+	}
+
+	var publicKey babyjub.PublicKeyComp
+	if len(publicKeyBytes) != len(publicKey) {
+		return fmt.Errorf("invalid public key length %d, want %d", len(publicKeyBytes), len(publicKey))
+	}
+	copy(publicKey[:], publicKeyBytes)
+
+	derived := NewBabyJubKeyPair(privateKey)
+	if derived.PublicKey.Compress() != publicKey {
+		return fmt.Errorf("public key does not match private key")
+	}
+
+	*k = *derived
+	return nil
+}
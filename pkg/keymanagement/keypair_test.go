@@ -0,0 +1,79 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package keymanagement_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/keymanagement"
+)
+
+func TestBabyJubKeyPair_SignVerify(t *testing.T) {
+	keyPair, err := keymanagement.Generate()
+	require.NoError(t, err)
+
+	signature := keyPair.Sign(big.NewInt(42))
+	require.True(t, keyPair.Verify(big.NewInt(42), signature))
+	require.False(t, keyPair.Verify(big.NewInt(43), signature))
+}
+
+func TestBabyJubKeyPair_JSON(t *testing.T) {
+	keyPair, err := keymanagement.Generate()
+	require.NoError(t, err)
+
+	data, err := json.Marshal(keyPair)
+	require.NoError(t, err)
+
+	var decoded keymanagement.BabyJubKeyPair
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, keyPair.PrivateKey, decoded.PrivateKey)
+	require.Equal(t, keyPair.PublicKey.Compress(), decoded.PublicKey.Compress())
+}
+
+func TestBabyJubKeyPair_UnmarshalJSON_invalid(t *testing.T) {
+	const privateKey = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	other, err := keymanagement.Generate()
+	require.NoError(t, err)
+
+	otherData, err := json.Marshal(other)
+	require.NoError(t, err)
+
+	var otherJSON map[string]string
+	require.NoError(t, json.Unmarshal(otherData, &otherJSON))
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "invalid private key hex", input: `{"privateKey":"zz","publicKey":""}`},
+		{name: "short private key", input: `{"privateKey":"0123","publicKey":""}`},
+		{name: "long private key", input: `{"privateKey":"` + privateKey + `00","publicKey":""}`},
+		{name: "short public key", input: `{"privateKey":"` + privateKey + `","publicKey":"0123"}`},
+		{name: "mismatching public key", input: `{"privateKey":"` + privateKey + `","publicKey":"` + otherJSON["publicKey"] + `"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keyPair keymanagement.BabyJubKeyPair
+			require.Error(t, json.Unmarshal([]byte(tt.input), &keyPair))
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle_test
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
+)
+
+func BenchmarkHashFunc(b *testing.B) {
+	for _, arity := range []int{2, 4, 6} {
+		inputs := make([]*big.Int, arity)
+		for i := range inputs {
+			inputs[i] = new(big.Int).Add(merkle.EmptyLeafValue.ToBig(), big.NewInt(int64(i)))
+		}
+
+		b.Run(fmt.Sprintf("arity_%d", arity), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := merkle.HashFunc(inputs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSetLeaf_1000(b *testing.B) {
+	const leavesAmount = 1000
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		tree, err := merkle.NewEmptyTree(10, merkle.EmptyLeafValue)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+
+		for j := 0; j < leavesAmount; j++ {
+			if err := tree.SetLeaf(j, merkle.TreeNode{Value: uint256.NewInt(uint64(j + 1))}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// CompactProof represents proofs of multiple leaves of the same tree with every node stored at most once.
+// Nodes that can be computed from the included leaves are omitted, e.g. proofs of two adjacent leaves share
+// all nodes above their common parent. Expand recomputes omitted nodes with HashFunc, so trees built with
+// a custom hash function are not supported.
+type CompactProof struct {
+	Depth  int                `json:"depth"`
+	Leaves []CompactProofNode `json:"leaves"`
+	Nodes  []CompactProofNode `json:"nodes"`
+}
+
+// CompactProofNode is a node of a CompactProof. Level 0 contains the leaves,
+// and Position is the index of the node within its level.
+type CompactProofNode struct {
+	Level    int      `json:"level"`
+	Position int      `json:"position"`
+	Value    TreeNode `json:"value"`
+}
+
+type compactProofPosition struct {
+	level, position int
+}
+
+// CompactProofFromProofs builds a CompactProof from proofs of the same tree.
+// All proofs must have the same depth, and nodes at the same position must be equal.
+func CompactProofFromProofs(proofs []Proof) (CompactProof, error) {
+	if len(proofs) == 0 {
+		return CompactProof{}, fmt.Errorf("no proofs given")
+	}
+
+	depth := proofs[0].Depth()
+
+	leaves := make(map[int]TreeNode, len(proofs))
+	ancestors := make(map[compactProofPosition]struct{})
+
+	for i, proof := range proofs {
+		if proof.Depth() != depth {
+			return CompactProof{}, fmt.Errorf("proof %d has depth %d, want %d", i, proof.Depth(), depth)
+		}
+
+		if proof.LeafIndex < 0 || proof.LeafIndex >= 1<<depth {
+			return CompactProof{}, fmt.Errorf("proof %d has invalid leaf index %d", i, proof.LeafIndex)
+		}
+
+		if leaf, ok := leaves[proof.LeafIndex]; ok && !leaf.Value.Eq(proof.Leaf.Value) {
+			return CompactProof{}, fmt.Errorf("proofs have different leaves at index %d", proof.LeafIndex)
+		}
+
+		leaves[proof.LeafIndex] = proof.Leaf
+
+		for level := 0; level <= depth; level++ {
+			ancestors[compactProofPosition{level: level, position: proof.LeafIndex >> level}] = struct{}{}
+		}
+	}
+
+	nodes := make(map[compactProofPosition]TreeNode)
+
+	for i, proof := range proofs {
+		for level, node := range proof.Path {
+			position := compactProofPosition{level: level, position: proof.LeafIndex>>level ^ 1}
+			if _, ok := ancestors[position]; ok {
+				continue
+			}
+
+			if existing, ok := nodes[position]; ok && !existing.Value.Eq(node.Value) {
+				return CompactProof{}, fmt.Errorf("proof %d conflicts with previous proofs at level %d", i, level)
+			}
+
+			nodes[position] = node
+		}
+	}
+
+	compactProof := CompactProof{
+		Depth:  depth,
+		Leaves: make([]CompactProofNode, 0, len(leaves)),
+		Nodes:  make([]CompactProofNode, 0, len(nodes)),
+	}
+
+	for index, leaf := range leaves {
+		compactProof.Leaves = append(compactProof.Leaves, CompactProofNode{Position: index, Value: leaf})
+	}
+
+	for position, node := range nodes {
+		compactProof.Nodes = append(compactProof.Nodes, CompactProofNode{
+			Level:    position.level,
+			Position: position.position,
+			Value:    node,
+		})
+	}
+
+	slices.SortFunc(compactProof.Leaves, compareCompactProofNodes)
+	slices.SortFunc(compactProof.Nodes, compareCompactProofNodes)
+
+	return compactProof, nil
+}
+
+// Expand reconstructs the proof of the leaf at the given index.
+func (c CompactProof) Expand(index int) (Proof, error) {
+	known := make(map[compactProofPosition]TreeNode, len(c.Leaves)+len(c.Nodes))
+	for _, node := range c.Nodes {
+		known[compactProofPosition{level: node.Level, position: node.Position}] = node.Value
+	}
+
+	for _, leaf := range c.Leaves {
+		known[compactProofPosition{level: 0, position: leaf.Position}] = leaf.Value
+	}
+
+	leaf, ok := known[compactProofPosition{level: 0, position: index}]
+	if !ok {
+		return Proof{}, fmt.Errorf("leaf %d is not included in the compact proof", index)
+	}
+
+	proof := Proof{
+		Leaf:      leaf,
+		LeafIndex: index,
+		Path:      make([]TreeNode, c.Depth),
+	}
+
+	for level := 0; level < c.Depth; level++ {
+		node, err := c.resolveNode(known, compactProofPosition{level: level, position: index>>level ^ 1})
+		if err != nil {
+			return Proof{}, err
+		}
+
+		proof.Path[level] = node
+	}
+
+	return proof, nil
+}
+
+// resolveNode returns the node at the given position, computing and caching it from its children if necessary.
+func (c CompactProof) resolveNode(known map[compactProofPosition]TreeNode, position compactProofPosition) (TreeNode, error) {
+	if node, ok := known[position]; ok {
+		return node, nil
+	}
+
+	if position.level == 0 {
+		return TreeNode{}, fmt.Errorf("node at level 0 position %d is missing", position.position)
+	}
+
+	left, err := c.resolveNode(known, compactProofPosition{level: position.level - 1, position: 2 * position.position})
+	if err != nil {
+		return TreeNode{}, err
+	}
+
+	right, err := c.resolveNode(known, compactProofPosition{level: position.level - 1, position: 2*position.position + 1})
+	if err != nil {
+		return TreeNode{}, err
+	}
+
+	node, err := computeNodeHash(HashFunc, left, right)
+	if err != nil {
+		return TreeNode{}, fmt.Errorf("compute hash: %w", err)
+	}
+
+	known[position] = node
+
+	return node, nil
+}
+
+func compareCompactProofNodes(a, b CompactProofNode) int {
+	if c := cmp.Compare(a.Level, b.Level); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(a.Position, b.Position)
+}
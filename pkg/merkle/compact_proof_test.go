@@ -0,0 +1,84 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle_test
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
+)
+
+func TestCompactProof(t *testing.T) {
+	tree, err := merkle.NewEmptyTree(3, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+
+	for i := 0; i < 8; i++ {
+		require.NoError(t, tree.SetLeaf(i, merkle.TreeNode{Value: uint256.NewInt(uint64(10 + i))}))
+	}
+
+	tests := []struct {
+		name          string
+		indices       []int
+		expectedNodes int
+	}{
+		{name: "single leaf", indices: []int{5}, expectedNodes: 3},
+		{name: "adjacent leaves", indices: []int{0, 1}, expectedNodes: 2},
+		{name: "distant leaves", indices: []int{0, 7}, expectedNodes: 4},
+		{name: "all leaves", indices: []int{0, 1, 2, 3, 4, 5, 6, 7}, expectedNodes: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proofs, err := tree.GetProofBatch(tt.indices)
+			require.NoError(t, err)
+
+			compactProof, err := merkle.CompactProofFromProofs(proofs)
+			require.NoError(t, err)
+			require.Equal(t, 3, compactProof.Depth)
+			require.Len(t, compactProof.Leaves, len(tt.indices))
+			require.Len(t, compactProof.Nodes, tt.expectedNodes)
+
+			for _, proof := range proofs {
+				expanded, err := compactProof.Expand(proof.LeafIndex)
+				require.NoError(t, err)
+				require.Equal(t, proof, expanded)
+			}
+		})
+	}
+}
+
+func TestCompactProof_invalid(t *testing.T) {
+	tree := makeTree(t)
+
+	proofs, err := tree.GetProofBatch([]int{0, 1})
+	require.NoError(t, err)
+
+	compactProof, err := merkle.CompactProofFromProofs(proofs)
+	require.NoError(t, err)
+
+	_, err = compactProof.Expand(2)
+	require.Error(t, err)
+
+	_, err = merkle.CompactProofFromProofs(nil)
+	require.Error(t, err)
+
+	proofs[1].Path = proofs[1].Path[:1]
+	_, err = merkle.CompactProofFromProofs(proofs)
+	require.Error(t, err)
+}
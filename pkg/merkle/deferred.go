@@ -0,0 +1,93 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// dirtySet is a bitset of node indices which hashes have to be recomputed.
+type dirtySet []uint64
+
+func newDirtySet(size int) dirtySet {
+	return make(dirtySet, (size+63)/64)
+}
+
+func (d dirtySet) set(i int) {
+	d[i/64] |= 1 << (i % 64)
+}
+
+func (d dirtySet) clear(i int) {
+	d[i/64] &^= 1 << (i % 64)
+}
+
+// WithDeferredHashing makes SetLeaf only mark the affected nodes as dirty instead of recomputing
+// their hashes immediately. Dirty nodes are hashed bottom-up by Flush, which is called automatically
+// by Root and proof generation methods. Internal nodes in Tree.Nodes are stale until Flush is called.
+func WithDeferredHashing() TreeOption {
+	return func(t *Tree) {
+		t.deferredHashing = true
+	}
+}
+
+// Flush recomputes hashes of all nodes marked as dirty since the last flush.
+// Every dirty node is hashed only once, even if multiple leaves below it were changed.
+func (t *Tree) Flush() error {
+	if !t.hasDirtyNodes {
+		return nil
+	}
+
+	depth := bits.Len(uint(t.GetLeavesAmount())) - 1
+
+	for level := depth - 1; level >= 0; level-- {
+		first, last := 1<<level-1, 1<<(level+1)-1
+
+		for w := first / 64; w <= (last-1)/64; w++ {
+			for word := t.dirty[w]; word != 0; word &= word - 1 {
+				i := w*64 + bits.TrailingZeros64(word)
+				if i < first || i >= last {
+					continue
+				}
+
+				node, err := t.computeChildrenHash(i)
+				if err != nil {
+					return fmt.Errorf("compute hash: %w", err)
+				}
+
+				t.Nodes[i] = node
+				t.dirty.clear(i)
+
+				if i > 0 {
+					t.dirty.set(GetParentIndex(i))
+				}
+			}
+		}
+	}
+
+	t.hasDirtyNodes = false
+
+	return nil
+}
+
+func (t *Tree) markDirty(j int) {
+	if j == 0 {
+		return
+	}
+
+	t.dirty.set(GetParentIndex(j))
+	t.hasDirtyNodes = true
+}
@@ -0,0 +1,72 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle_test
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
+)
+
+func TestTree_DeferredHashing(t *testing.T) {
+	depth := 7
+
+	expected, err := merkle.NewEmptyTree(depth, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+
+	tree, err := merkle.NewEmptyTree(depth, merkle.EmptyLeafValue, merkle.WithDeferredHashing())
+	require.NoError(t, err)
+
+	for _, i := range []int{0, 1, 5, 64, 100, 127} {
+		node := merkle.TreeNode{Value: uint256.NewInt(uint64(i + 1))}
+
+		require.NoError(t, expected.SetLeaf(i, node))
+		require.NoError(t, tree.SetLeaf(i, node))
+	}
+
+	require.True(t, expected.Root().Value.Eq(tree.Root().Value), "invalid merkle root")
+	require.True(t, areTreeNodeSlicesEqual(expected.Nodes, tree.Nodes))
+}
+
+func TestTree_DeferredHashing_proof(t *testing.T) {
+	expected := makeTree(t)
+
+	tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue, merkle.WithDeferredHashing())
+	require.NoError(t, err)
+	require.NoError(t, tree.SetLeaf(0, merkle.TreeNode{Value: uint256.NewInt(10)}))
+	require.NoError(t, tree.SetLeaf(1, merkle.TreeNode{Value: uint256.NewInt(20)}))
+	require.NoError(t, tree.SetLeaf(2, merkle.TreeNode{Value: uint256.NewInt(30)}))
+	require.NoError(t, tree.SetLeaf(3, merkle.TreeNode{Value: uint256.NewInt(40)}))
+
+	proof, err := tree.GetProof(2)
+	require.NoError(t, err)
+
+	expectedProof, err := expected.GetProof(2)
+	require.NoError(t, err)
+	require.True(t, areTreeNodeSlicesEqual(expectedProof.Path, proof.Path), "proof paths are not equal")
+}
+
+func TestTree_DeferredHashing_notInField(t *testing.T) {
+	tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue, merkle.WithDeferredHashing())
+	require.NoError(t, err)
+
+	err = tree.SetLeaf(0, merkle.TreeNode{Value: new(uint256.Int).SetAllOne()})
+	require.Error(t, err)
+	require.NoError(t, tree.Flush())
+}
@@ -0,0 +1,92 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle
+
+import "fmt"
+
+// TreeNodeUpdate describes a change of a single leaf.
+type TreeNodeUpdate struct {
+	LeafIndex int      `json:"leafIndex"`
+	OldValue  TreeNode `json:"oldValue"`
+	NewValue  TreeNode `json:"newValue"`
+}
+
+// Diff returns the leaf updates turning the tree into the other tree of the same depth.
+func (t *Tree) Diff(other *Tree) ([]TreeNodeUpdate, error) {
+	if err := t.checkNodes(); err != nil {
+		return nil, err
+	}
+
+	if err := other.checkNodes(); err != nil {
+		return nil, err
+	}
+
+	leavesAmount := t.GetLeavesAmount()
+	if other.GetLeavesAmount() != leavesAmount {
+		return nil, fmt.Errorf("trees have different amount of leaves")
+	}
+
+	offset := len(t.Nodes) - leavesAmount
+
+	var diff []TreeNodeUpdate
+	for i := 0; i < leavesAmount; i++ {
+		oldValue, newValue := t.Nodes[offset+i], other.Nodes[offset+i]
+		if !oldValue.Value.Eq(newValue.Value) {
+			diff = append(diff, TreeNodeUpdate{LeafIndex: i, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+
+	return diff, nil
+}
+
+// ApplyDiff applies the leaf updates to the tree. All updates are validated before any of them is applied:
+// leaf indices must be in range and unique within the diff, the old values must match the current leaves,
+// and the new values must be in the field.
+func (t *Tree) ApplyDiff(diff []TreeNodeUpdate) error {
+	seen := make(map[int]struct{}, len(diff))
+
+	for _, update := range diff {
+		if _, ok := seen[update.LeafIndex]; ok {
+			return fmt.Errorf("leaf %d is updated more than once", update.LeafIndex)
+		}
+		seen[update.LeafIndex] = struct{}{}
+
+		leaf, err := t.GetLeaf(update.LeafIndex)
+		if err != nil {
+			return fmt.Errorf("leaf %d: %w", update.LeafIndex, err)
+		}
+
+		if update.OldValue.Value == nil || !leaf.Value.Eq(update.OldValue.Value) {
+			return fmt.Errorf("leaf %d does not match the old value of the diff", update.LeafIndex)
+		}
+
+		if update.NewValue.Value == nil {
+			return fmt.Errorf("leaf %d has no new value", update.LeafIndex)
+		}
+
+		if !update.NewValue.Value.Lt(fieldModulus) {
+			return fmt.Errorf("new value of leaf %d is not in the field", update.LeafIndex)
+		}
+	}
+
+	for _, update := range diff {
+		if err := t.SetLeaf(update.LeafIndex, update.NewValue); err != nil {
+			return fmt.Errorf("set leaf %d: %w", update.LeafIndex, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle_test
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
+)
+
+func TestTree_ApplyDiff(t *testing.T) {
+	base := makeTree(t)
+
+	target := makeTree(t)
+	require.NoError(t, target.SetLeaf(1, merkle.TreeNode{Value: uint256.NewInt(50)}))
+	require.NoError(t, target.SetLeaf(3, merkle.TreeNode{Value: merkle.EmptyLeafValue}))
+
+	diff, err := base.Diff(target)
+	require.NoError(t, err)
+	require.Len(t, diff, 2)
+	require.Equal(t, 1, diff[0].LeafIndex)
+	require.Equal(t, 3, diff[1].LeafIndex)
+
+	require.NoError(t, base.ApplyDiff(diff))
+	require.True(t, areTreeNodeSlicesEqual(target.Nodes, base.Nodes))
+
+	require.ErrorContains(t, base.ApplyDiff(diff), "leaf 1 ")
+}
+
+func TestTree_ApplyDiff_invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		diff []merkle.TreeNodeUpdate
+	}{
+		{
+			name: "index out of range",
+			diff: []merkle.TreeNodeUpdate{{
+				LeafIndex: 4,
+				OldValue:  merkle.TreeNode{Value: merkle.EmptyLeafValue},
+				NewValue:  merkle.TreeNode{Value: uint256.NewInt(1)},
+			}},
+		},
+		{
+			name: "old value mismatch",
+			diff: []merkle.TreeNodeUpdate{
+				{LeafIndex: 0, OldValue: merkle.TreeNode{Value: uint256.NewInt(10)}, NewValue: merkle.TreeNode{Value: uint256.NewInt(1)}},
+				{LeafIndex: 1, OldValue: merkle.TreeNode{Value: uint256.NewInt(10)}, NewValue: merkle.TreeNode{Value: uint256.NewInt(1)}},
+			},
+		},
+		{
+			name: "missing new value",
+			diff: []merkle.TreeNodeUpdate{{LeafIndex: 0, OldValue: merkle.TreeNode{Value: uint256.NewInt(10)}}},
+		},
+		{
+			name: "new value not in field",
+			diff: []merkle.TreeNodeUpdate{
+				{LeafIndex: 0, OldValue: merkle.TreeNode{Value: uint256.NewInt(10)}, NewValue: merkle.TreeNode{Value: uint256.NewInt(1)}},
+				{LeafIndex: 1, OldValue: merkle.TreeNode{Value: uint256.NewInt(20)}, NewValue: merkle.TreeNode{Value: new(uint256.Int).SetAllOne()}},
+			},
+		},
+		{
+			name: "duplicate index",
+			diff: []merkle.TreeNodeUpdate{
+				{LeafIndex: 0, OldValue: merkle.TreeNode{Value: uint256.NewInt(10)}, NewValue: merkle.TreeNode{Value: uint256.NewInt(1)}},
+				{LeafIndex: 0, OldValue: merkle.TreeNode{Value: uint256.NewInt(10)}, NewValue: merkle.TreeNode{Value: uint256.NewInt(2)}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := makeTree(t)
+
+			require.Error(t, tree.ApplyDiff(tt.diff))
+			require.True(t, areTreeNodeSlicesEqual(makeTree(t).Nodes, tree.Nodes))
+		})
+	}
+}
+
+func TestTree_Diff_differentDepth(t *testing.T) {
+	other, err := merkle.NewEmptyTreeWithDepth(3)
+	require.NoError(t, err)
+
+	_, err = makeTree(t).Diff(other)
+	require.Error(t, err)
+}
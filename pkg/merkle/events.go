@@ -0,0 +1,194 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/holiman/uint256"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/contracts"
+)
+
+// TODO: Deployed contract doesn't emit these events, it emits an older version of them
+var (
+	eventLeafAddition   = crypto.Keccak256Hash([]byte("zkCertificateAddition(bytes32,address,uint256)"))
+	eventLeafRevocation = crypto.Keccak256Hash([]byte("zkCertificateRevocation(bytes32,address,uint256)"))
+)
+
+// eventQueryBlockRange is the maximum amount of blocks queried for registry events at once,
+// because RPC providers commonly limit the block range of log queries.
+const eventQueryBlockRange = 10_000
+
+// ReplayOption configures optional behaviour of ReplayEthereumEvents.
+type ReplayOption func(c *replayConfig)
+
+type replayConfig struct {
+	progress func(blocks uint64)
+}
+
+// WithReplayProgress makes ReplayEthereumEvents call fn with the amount of blocks processed
+// after each queried block range, e.g. to display a progress bar.
+func WithReplayProgress(fn func(blocks uint64)) ReplayOption {
+	return func(c *replayConfig) {
+		c.progress = fn
+	}
+}
+
+// NewTreeFromEthereumEvents reconstructs the Merkle tree of a registry contract by replaying its
+// certificate addition and revocation events emitted in the block range [fromBlock, toBlock].
+// The range must start at or before the registry deployment for the resulting root to match the on-chain root.
+func NewTreeFromEthereumEvents(
+	ctx context.Context,
+	client *ethclient.Client,
+	registryAddr common.Address,
+	fromBlock, toBlock uint64,
+	opts ...ReplayOption,
+) (*Tree, error) {
+	tree, err := NewEmptyTree(TreeDepth, EmptyLeafValue, WithDeferredHashing())
+	if err != nil {
+		return nil, fmt.Errorf("initialize empty tree: %w", err)
+	}
+
+	if err := ReplayEthereumEvents(ctx, client, registryAddr, fromBlock, toBlock, tree, opts...); err != nil {
+		return nil, err
+	}
+
+	return tree, nil
+}
+
+// ReplayEthereumEvents applies the registry events emitted in the block range [fromBlock, toBlock] to the tree
+// in the order they were emitted, and checks that the resulting root equals the registry root at toBlock.
+// Events are queried in block ranges of at most 10'000 blocks.
+// It can be used to bring a tree restored from a snapshot up to date.
+func ReplayEthereumEvents(
+	ctx context.Context,
+	client *ethclient.Client,
+	registryAddr common.Address,
+	fromBlock, toBlock uint64,
+	tree *Tree,
+	opts ...ReplayOption,
+) error {
+	var cfg replayConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	registry, err := contracts.NewZkCertificateRegistry(registryAddr, client)
+	if err != nil {
+		return fmt.Errorf("bind registry contract: %w", err)
+	}
+
+	for from := fromBlock; from <= toBlock; {
+		to := min(toBlock, from+eventQueryBlockRange-1)
+
+		logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: []common.Address{registryAddr},
+			Topics:    [][]common.Hash{{eventLeafAddition, eventLeafRevocation}},
+		})
+		if err != nil {
+			return fmt.Errorf("execute filter query: %w", err)
+		}
+
+		for _, logEntry := range logs {
+			if err := applyRegistryEvent(&registry.ZkCertificateRegistryFilterer, logEntry, tree); err != nil {
+				return fmt.Errorf("apply event of transaction %s: %w", logEntry.TxHash, err)
+			}
+		}
+
+		if cfg.progress != nil {
+			cfg.progress(to - from + 1)
+		}
+
+		if to == toBlock {
+			break
+		}
+
+		from = to + 1
+	}
+
+	if err := tree.Flush(); err != nil {
+		return fmt.Errorf("flush tree: %w", err)
+	}
+
+	onChainRoot, err := registry.MerkleRoot(&bind.CallOpts{
+		Context:     ctx,
+		BlockNumber: new(big.Int).SetUint64(toBlock),
+	})
+	if err != nil {
+		return fmt.Errorf("retrieve on-chain root: %w", err)
+	}
+
+	if tree.Root().Value.Bytes32() != onChainRoot {
+		return fmt.Errorf("tree root does not match on-chain root %s", common.Hash(onChainRoot))
+	}
+
+	return nil
+}
+
+func applyRegistryEvent(filterer *contracts.ZkCertificateRegistryFilterer, logEntry types.Log, tree *Tree) error {
+	if logEntry.Removed {
+		return fmt.Errorf("not supported: log is removed due to chain reorganisation")
+	}
+
+	if len(logEntry.Topics) == 0 {
+		return fmt.Errorf("log has no topics")
+	}
+
+	var index *big.Int
+	var leafValue *uint256.Int
+
+	switch logEntry.Topics[0] {
+	case eventLeafAddition:
+		event, err := filterer.ParseZkCertificateAddition(logEntry)
+		if err != nil {
+			return fmt.Errorf("parse addition event: %w", err)
+		}
+
+		index = event.Index
+		leafValue = new(uint256.Int).SetBytes32(event.ZkCertificateLeafHash[:])
+	case eventLeafRevocation:
+		event, err := filterer.ParseZkCertificateRevocation(logEntry)
+		if err != nil {
+			return fmt.Errorf("parse revocation event: %w", err)
+		}
+
+		index = event.Index
+		leafValue = EmptyLeafValue
+	default:
+		return fmt.Errorf("unexpected event %s", logEntry.Topics[0])
+	}
+
+	if !index.IsInt64() {
+		return fmt.Errorf("invalid leaf index %s", index)
+	}
+
+	if err := tree.SetLeaf(int(index.Int64()), TreeNode{Value: leafValue}); err != nil {
+		return fmt.Errorf("set leaf %s: %w", index, err)
+	}
+
+	return nil
+}
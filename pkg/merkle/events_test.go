@@ -0,0 +1,146 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
+)
+
+// fakeEthService serves the eth_getLogs and eth_call methods used to replay registry events.
+type fakeEthService struct {
+	logs    []types.Log
+	root    [32]byte
+	queries int
+}
+
+func (s *fakeEthService) GetLogs(_ context.Context, _ map[string]interface{}) ([]types.Log, error) {
+	s.queries++
+	return s.logs, nil
+}
+
+func (s *fakeEthService) Call(_ context.Context, _ map[string]interface{}, _ string) (hexutil.Bytes, error) {
+	return s.root[:], nil
+}
+
+func dialFakeEthService(t *testing.T, service *fakeEthService) *ethclient.Client {
+	t.Helper()
+
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", service))
+	t.Cleanup(server.Stop)
+
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+func makeRegistryLog(registry common.Address, event string, leafHash uint64, index uint64) types.Log {
+	leafHashBytes := uint256.NewInt(leafHash).Bytes32()
+	indexBytes := uint256.NewInt(index).Bytes32()
+
+	return types.Log{
+		Address: registry,
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte(event + "(bytes32,address,uint256)")),
+			leafHashBytes,
+			common.BytesToHash(common.HexToAddress("0x1000000000000000000000000000000000000001").Bytes()),
+		},
+		Data: indexBytes[:],
+	}
+}
+
+func TestReplayEthereumEvents(t *testing.T) {
+	registry := common.HexToAddress("0x8eD8311ee2F3de2b3e8C6E0c7A5bA1d11DB28aB1")
+
+	expected, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+	require.NoError(t, expected.SetLeaf(0, merkle.TreeNode{Value: uint256.NewInt(10)}))
+	require.NoError(t, expected.SetLeaf(2, merkle.TreeNode{Value: uint256.NewInt(30)}))
+
+	service := &fakeEthService{
+		logs: []types.Log{
+			makeRegistryLog(registry, "zkCertificateAddition", 10, 0),
+			makeRegistryLog(registry, "zkCertificateAddition", 20, 1),
+			makeRegistryLog(registry, "zkCertificateAddition", 30, 2),
+			makeRegistryLog(registry, "zkCertificateRevocation", 20, 1),
+		},
+		root: expected.Root().Value.Bytes32(),
+	}
+	client := dialFakeEthService(t, service)
+
+	tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+	require.NoError(t, merkle.ReplayEthereumEvents(context.Background(), client, registry, 0, 100, tree))
+	require.Equal(t, expected.Nodes, tree.Nodes)
+
+	service.root = [32]byte{1}
+	tree, err = merkle.NewEmptyTree(2, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+	require.ErrorContains(
+		t,
+		merkle.ReplayEthereumEvents(context.Background(), client, registry, 0, 100, tree),
+		"does not match on-chain root",
+	)
+}
+
+func TestReplayEthereumEvents_removedLog(t *testing.T) {
+	registry := common.HexToAddress("0x8eD8311ee2F3de2b3e8C6E0c7A5bA1d11DB28aB1")
+
+	logEntry := makeRegistryLog(registry, "zkCertificateAddition", 10, 0)
+	logEntry.Removed = true
+
+	client := dialFakeEthService(t, &fakeEthService{logs: []types.Log{logEntry}})
+
+	tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+	require.ErrorContains(
+		t,
+		merkle.ReplayEthereumEvents(context.Background(), client, registry, 0, 100, tree),
+		"chain reorganisation",
+	)
+}
+
+func TestReplayEthereumEvents_blockRanges(t *testing.T) {
+	registry := common.HexToAddress("0x8eD8311ee2F3de2b3e8C6E0c7A5bA1d11DB28aB1")
+
+	tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+
+	service := &fakeEthService{root: tree.Root().Value.Bytes32()}
+	client := dialFakeEthService(t, service)
+
+	var blocks []uint64
+	progress := merkle.WithReplayProgress(func(n uint64) {
+		blocks = append(blocks, n)
+	})
+
+	require.NoError(t, merkle.ReplayEthereumEvents(context.Background(), client, registry, 5, 25_004, tree, progress))
+	require.Equal(t, 3, service.queries)
+	require.Equal(t, []uint64{10_000, 10_000, 5_000}, blocks)
+}
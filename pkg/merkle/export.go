@@ -0,0 +1,101 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+type exportHeader struct {
+	Depth int `json:"depth"`
+}
+
+type exportEntry struct {
+	Index int      `json:"index"`
+	Value TreeNode `json:"value"`
+}
+
+// Export writes the tree in JSON-lines format. The first line holds the tree depth as {"depth": N},
+// every following line holds a single non-empty leaf as {"index": N, "value": "decimal_string"}.
+func (t *Tree) Export(w io.Writer) error {
+	if err := t.checkNodes(); err != nil {
+		return err
+	}
+
+	leavesAmount := t.GetLeavesAmount()
+	offset := len(t.Nodes) - leavesAmount
+
+	encoder := json.NewEncoder(w)
+
+	if err := encoder.Encode(exportHeader{Depth: bits.Len(uint(leavesAmount)) - 1}); err != nil {
+		return fmt.Errorf("encode header: %w", err)
+	}
+
+	for i, leaf := range t.Nodes[offset:] {
+		if leaf.IsEmpty() {
+			continue
+		}
+
+		if err := encoder.Encode(exportEntry{Index: i, Value: leaf}); err != nil {
+			return fmt.Errorf("encode leaf %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Import reads a tree written by Export. Leaves are read and set one by one
+// without buffering the whole input.
+func Import(r io.Reader) (*Tree, error) {
+	decoder := json.NewDecoder(r)
+
+	var header exportHeader
+	if err := decoder.Decode(&header); err != nil {
+		return nil, fmt.Errorf("decode header: %w", err)
+	}
+
+	if header.Depth > TreeDepth {
+		return nil, fmt.Errorf("tree depth %d exceeds maximum %d", header.Depth, TreeDepth)
+	}
+
+	tree, err := NewEmptyTree(header.Depth, EmptyLeafValue)
+	if err != nil {
+		return nil, fmt.Errorf("initialize empty tree: %w", err)
+	}
+
+	for {
+		var entry exportEntry
+		if err := decoder.Decode(&entry); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("decode leaf: %w", err)
+		}
+
+		if entry.Value.Value == nil {
+			return nil, fmt.Errorf("missing value of leaf %d", entry.Index)
+		}
+
+		if err := tree.SetLeaf(entry.Index, entry.Value); err != nil {
+			return nil, fmt.Errorf("set leaf %d: %w", entry.Index, err)
+		}
+	}
+
+	return tree, nil
+}
@@ -0,0 +1,64 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
+)
+
+func TestTree_Export(t *testing.T) {
+	tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+	require.NoError(t, tree.SetLeaf(1, merkle.TreeNode{Value: uint256.NewInt(20)}))
+	require.NoError(t, tree.SetLeaf(3, merkle.TreeNode{Value: uint256.NewInt(40)}))
+
+	var buf bytes.Buffer
+	require.NoError(t, tree.Export(&buf))
+	require.Equal(t, `{"depth":2}
+{"index":1,"value":"20"}
+{"index":3,"value":"40"}
+`, buf.String())
+
+	imported, err := merkle.Import(&buf)
+	require.NoError(t, err)
+	require.True(t, areTreeNodeSlicesEqual(tree.Nodes, imported.Nodes))
+}
+
+func TestImport_invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "Empty input", input: ""},
+		{name: "Invalid leaf index", input: `{"depth":2}` + "\n" + `{"index":4,"value":"20"}`},
+		{name: "Missing value", input: `{"depth":2}` + "\n" + `{"index":1}`},
+		{name: "Invalid value", input: `{"depth":2}` + "\n" + `{"index":1,"value":"abc"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := merkle.Import(strings.NewReader(tt.input))
+			require.Error(t, err)
+		})
+	}
+}
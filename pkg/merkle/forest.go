@@ -0,0 +1,79 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MerkleForest manages Merkle trees of multiple registry contracts indexed by the registry address.
+// It is safe for concurrent use, the trees themselves are not.
+type MerkleForest struct {
+	mu    sync.RWMutex
+	trees map[common.Address]*Tree
+}
+
+// NewMerkleForest creates an empty forest.
+func NewMerkleForest() *MerkleForest {
+	return &MerkleForest{trees: make(map[common.Address]*Tree)}
+}
+
+// GetTree returns the tree of the given registry and reports whether it exists.
+func (f *MerkleForest) GetTree(registryAddress common.Address) (*Tree, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	tree, ok := f.trees[registryAddress]
+	return tree, ok
+}
+
+// AddTree adds the tree of the given registry. It fails if the registry already has a tree.
+func (f *MerkleForest) AddTree(registryAddress common.Address, tree *Tree) error {
+	if tree == nil {
+		return fmt.Errorf("tree is nil")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.trees[registryAddress]; ok {
+		return fmt.Errorf("registry %s already has a tree", registryAddress.Hex())
+	}
+
+	f.trees[registryAddress] = tree
+	return nil
+}
+
+// RemoveTree removes the tree of the given registry, if any.
+func (f *MerkleForest) RemoveTree(registryAddress common.Address) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.trees, registryAddress)
+}
+
+// GetProof generates a proof of the leaf in the tree of the given registry.
+func (f *MerkleForest) GetProof(registryAddress common.Address, leafIndex int) (Proof, error) {
+	tree, ok := f.GetTree(registryAddress)
+	if !ok {
+		return Proof{}, fmt.Errorf("registry %s has no tree", registryAddress.Hex())
+	}
+
+	return tree.GetProof(leafIndex)
+}
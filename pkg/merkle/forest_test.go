@@ -0,0 +1,58 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
+)
+
+func TestMerkleForest(t *testing.T) {
+	registry := common.HexToAddress("0x8eD8311ee2F3de2b3e8C6E0c7A5bA1d11DB28aB1")
+	otherRegistry := common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	forest := merkle.NewMerkleForest()
+	tree := makeTree(t)
+
+	require.NoError(t, forest.AddTree(registry, tree))
+	require.Error(t, forest.AddTree(registry, makeTree(t)))
+	require.Error(t, forest.AddTree(otherRegistry, nil))
+
+	got, ok := forest.GetTree(registry)
+	require.True(t, ok)
+	require.Same(t, tree, got)
+
+	_, ok = forest.GetTree(otherRegistry)
+	require.False(t, ok)
+
+	proof, err := forest.GetProof(registry, 1)
+	require.NoError(t, err)
+
+	expectedProof, err := tree.GetProof(1)
+	require.NoError(t, err)
+	require.Equal(t, expectedProof, proof)
+
+	_, err = forest.GetProof(otherRegistry, 1)
+	require.Error(t, err)
+
+	forest.RemoveTree(registry)
+	_, ok = forest.GetTree(registry)
+	require.False(t, ok)
+}
@@ -0,0 +1,114 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// RootHistoryEntry describes a single tree update and the tree root that resulted from it.
+// For a leaf update, LeafIndex and LeafValue describe the updated leaf. For a Prune call, Pruned is set,
+// LeafIndex is the first kept leaf index and all leaves before it were set to LeafValue.
+type RootHistoryEntry struct {
+	LeafIndex int
+	LeafValue TreeNode
+	Root      TreeNode
+	Timestamp time.Time
+	Pruned    bool
+}
+
+type rootHistory struct {
+	createdAt   time.Time
+	initialRoot TreeNode
+	entries     []RootHistoryEntry
+}
+
+// WithHistory enables recording of the tree root after every SetLeaf and Prune call.
+func WithHistory() TreeOption {
+	return func(t *Tree) {
+		t.history = &rootHistory{}
+	}
+}
+
+// RootHistory returns the append-only log of leaf updates recorded since the tree creation.
+// It returns nil if the tree was created without WithHistory option.
+func (t *Tree) RootHistory() []RootHistoryEntry {
+	if t.history == nil {
+		return nil
+	}
+
+	return t.history.entries
+}
+
+// GetRootAt returns the root of the tree that was valid at the given time.
+func (t *Tree) GetRootAt(timestamp time.Time) (TreeNode, error) {
+	if t.history == nil {
+		return TreeNode{}, fmt.Errorf("tree history is not enabled")
+	}
+
+	if timestamp.Before(t.history.createdAt) {
+		return TreeNode{}, fmt.Errorf("tree did not exist at %s", timestamp)
+	}
+
+	entries := t.history.entries
+
+	i := sort.Search(len(entries), func(i int) bool {
+		return entries[i].Timestamp.After(timestamp)
+	})
+	if i == 0 {
+		return t.history.initialRoot, nil
+	}
+
+	return entries[i-1].Root, nil
+}
+
+func (h *rootHistory) start(initialRoot TreeNode) {
+	if h == nil {
+		return
+	}
+
+	h.createdAt = time.Now()
+	h.initialRoot = initialRoot
+}
+
+func (h *rootHistory) record(leafIndex int, leafValue TreeNode, root TreeNode) {
+	if h == nil {
+		return
+	}
+
+	h.entries = append(h.entries, RootHistoryEntry{
+		LeafIndex: leafIndex,
+		LeafValue: leafValue,
+		Root:      root,
+		Timestamp: time.Now(),
+	})
+}
+
+func (h *rootHistory) recordPrune(keepFromLeafIndex int, emptyLeaf TreeNode, root TreeNode) {
+	if h == nil {
+		return
+	}
+
+	h.entries = append(h.entries, RootHistoryEntry{
+		LeafIndex: keepFromLeafIndex,
+		LeafValue: emptyLeaf,
+		Root:      root,
+		Timestamp: time.Now(),
+		Pruned:    true,
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
+)
+
+func TestTree_RootHistory(t *testing.T) {
+	tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue, merkle.WithHistory())
+	require.NoError(t, err)
+
+	initialRoot := tree.Root()
+
+	require.NoError(t, tree.SetLeaf(0, merkle.TreeNode{Value: uint256.NewInt(10)}))
+	firstRoot := tree.Root()
+
+	require.NoError(t, tree.SetLeaf(1, merkle.TreeNode{Value: uint256.NewInt(20)}))
+
+	history := tree.RootHistory()
+	require.Len(t, history, 2)
+	require.Equal(t, 0, history[0].LeafIndex)
+	require.Equal(t, 1, history[1].LeafIndex)
+	require.True(t, history[1].Root.Value.Eq(tree.Root().Value))
+
+	root, err := tree.GetRootAt(history[0].Timestamp.Add(-time.Nanosecond))
+	require.NoError(t, err)
+	require.True(t, initialRoot.Value.Eq(root.Value))
+
+	root, err = tree.GetRootAt(history[0].Timestamp)
+	require.NoError(t, err)
+	require.True(t, firstRoot.Value.Eq(root.Value))
+
+	root, err = tree.GetRootAt(time.Now())
+	require.NoError(t, err)
+	require.True(t, tree.Root().Value.Eq(root.Value))
+
+	_, err = tree.GetRootAt(time.Unix(0, 0))
+	require.Error(t, err)
+}
+
+func TestTree_RootHistory_prune(t *testing.T) {
+	tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue, merkle.WithHistory())
+	require.NoError(t, err)
+
+	require.NoError(t, tree.SetLeaf(0, merkle.TreeNode{Value: uint256.NewInt(10)}))
+	require.NoError(t, tree.SetLeaf(2, merkle.TreeNode{Value: uint256.NewInt(30)}))
+	require.NoError(t, tree.Prune(2))
+
+	history := tree.RootHistory()
+	require.Len(t, history, 3)
+	require.False(t, history[1].Pruned)
+	require.True(t, history[2].Pruned)
+	require.Equal(t, 2, history[2].LeafIndex)
+	require.True(t, merkle.EmptyLeafValue.Eq(history[2].LeafValue.Value))
+	require.True(t, tree.Root().Value.Eq(history[2].Root.Value))
+}
+
+func TestTree_RootHistory_disabled(t *testing.T) {
+	tree := makeTree(t)
+
+	require.Nil(t, tree.RootHistory())
+
+	_, err := tree.GetRootAt(time.Now())
+	require.Error(t, err)
+}
@@ -0,0 +1,98 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle
+
+import (
+	"fmt"
+	"slices"
+)
+
+// MultiProof proves the membership of multiple leaves at once, sharing sibling nodes between the paths.
+// It follows the layout of the OpenZeppelin MerkleProof multi-proof: Leaves are ordered by descending
+// leaf index, Proof holds the sibling nodes that can not be computed from the leaves, and ProofFlags
+// tells for every hashing step whether the second operand is the next computed node (true) or the next Proof node (false).
+//
+// Note that the OpenZeppelin verifier hashes node pairs with commutative keccak256, while this tree
+// hashes ordered pairs with Poseidon, so the proof must be verified with the tree hash function.
+type MultiProof struct {
+	Leaves     []TreeNode `json:"leaves"`
+	Proof      []TreeNode `json:"proof"`
+	ProofFlags []bool     `json:"proofFlags"`
+}
+
+// GetMultiProof generates a multi-proof for the leaves at the given indices.
+// Duplicate indices are rejected. If no indices are given, the proof consists of the root only.
+func (t *Tree) GetMultiProof(indices []int) (MultiProof, error) {
+	if err := t.checkNodes(); err != nil {
+		return MultiProof{}, err
+	}
+
+	if err := t.Flush(); err != nil {
+		return MultiProof{}, err
+	}
+
+	leavesAmount := t.GetLeavesAmount()
+	offset := len(t.Nodes) - leavesAmount
+
+	stack := make([]int, len(indices))
+	for i, index := range indices {
+		if index >= leavesAmount || index < 0 {
+			return MultiProof{}, fmt.Errorf("invalid leaf index %d", index)
+		}
+
+		stack[i] = offset + index
+	}
+
+	slices.Sort(stack)
+	slices.Reverse(stack)
+
+	for i := 1; i < len(stack); i++ {
+		if stack[i] == stack[i-1] {
+			return MultiProof{}, fmt.Errorf("duplicate leaf index %d", stack[i]-offset)
+		}
+	}
+
+	multiProof := MultiProof{
+		Leaves:     make([]TreeNode, len(stack)),
+		Proof:      []TreeNode{},
+		ProofFlags: []bool{},
+	}
+
+	for i, j := range stack {
+		multiProof.Leaves[i] = t.Nodes[j]
+	}
+
+	for len(stack) > 0 && stack[0] > 0 {
+		j := stack[0]
+		stack = stack[1:]
+
+		if siblingIndex := GetSiblingIndex(j); len(stack) > 0 && stack[0] == siblingIndex {
+			multiProof.ProofFlags = append(multiProof.ProofFlags, true)
+			stack = stack[1:]
+		} else {
+			multiProof.ProofFlags = append(multiProof.ProofFlags, false)
+			multiProof.Proof = append(multiProof.Proof, t.Nodes[siblingIndex])
+		}
+
+		stack = append(stack, GetParentIndex(j))
+	}
+
+	if len(indices) == 0 {
+		multiProof.Proof = append(multiProof.Proof, t.Nodes[0])
+	}
+
+	return multiProof, nil
+}
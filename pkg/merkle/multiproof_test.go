@@ -0,0 +1,90 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
+)
+
+func TestTree_GetMultiProof(t *testing.T) {
+	tree := makeTree(t)
+	nodes := tree.Nodes
+
+	tests := []struct {
+		name     string
+		indices  []int
+		expected merkle.MultiProof
+	}{
+		{
+			name:    "siblings",
+			indices: []int{0, 1},
+			expected: merkle.MultiProof{
+				Leaves:     []merkle.TreeNode{nodes[4], nodes[3]},
+				Proof:      []merkle.TreeNode{nodes[2]},
+				ProofFlags: []bool{true, false},
+			},
+		},
+		{
+			name:    "distant leaves",
+			indices: []int{3, 0},
+			expected: merkle.MultiProof{
+				Leaves:     []merkle.TreeNode{nodes[6], nodes[3]},
+				Proof:      []merkle.TreeNode{nodes[5], nodes[4]},
+				ProofFlags: []bool{false, false, true},
+			},
+		},
+		{
+			name:    "all leaves",
+			indices: []int{0, 1, 2, 3},
+			expected: merkle.MultiProof{
+				Leaves:     []merkle.TreeNode{nodes[6], nodes[5], nodes[4], nodes[3]},
+				Proof:      []merkle.TreeNode{},
+				ProofFlags: []bool{true, true, true},
+			},
+		},
+		{
+			name:    "no leaves",
+			indices: nil,
+			expected: merkle.MultiProof{
+				Leaves:     []merkle.TreeNode{},
+				Proof:      []merkle.TreeNode{nodes[0]},
+				ProofFlags: []bool{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			multiProof, err := tree.GetMultiProof(tt.indices)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, multiProof)
+		})
+	}
+}
+
+func TestTree_GetMultiProof_invalidIndices(t *testing.T) {
+	tree := makeTree(t)
+
+	_, err := tree.GetMultiProof([]int{1, 1})
+	require.ErrorContains(t, err, "duplicate leaf index 1")
+
+	_, err = tree.GetMultiProof([]int{4})
+	require.ErrorContains(t, err, "invalid leaf index 4")
+}
@@ -0,0 +1,233 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/holiman/uint256"
+)
+
+// GetProofBatch generates proofs for multiple leaves at once. The tree is traversed level by level
+// a single time, and sibling nodes shared between paths are fetched only once.
+// Proofs are returned in the order of the given indices.
+func (t *Tree) GetProofBatch(indices []int) ([]Proof, error) {
+	if err := t.checkNodes(); err != nil {
+		return nil, err
+	}
+
+	if err := t.Flush(); err != nil {
+		return nil, err
+	}
+
+	leavesAmount := t.GetLeavesAmount()
+	offset := len(t.Nodes) - leavesAmount
+
+	levelNodes := make([]int, len(indices))
+	for i, index := range indices {
+		if index >= leavesAmount || index < 0 {
+			return nil, fmt.Errorf("invalid leaf index %d", index)
+		}
+
+		levelNodes[i] = offset + index
+	}
+
+	slices.Sort(levelNodes)
+	levelNodes = slices.Compact(levelNodes)
+
+	siblings := make(map[int]TreeNode)
+
+	for len(levelNodes) > 0 && levelNodes[0] > 0 {
+		parentNodes := levelNodes[:0]
+
+		for _, j := range levelNodes {
+			siblingIndex := GetSiblingIndex(j)
+			if _, ok := siblings[siblingIndex]; !ok {
+				siblings[siblingIndex] = t.Nodes[siblingIndex]
+			}
+
+			if parentIndex := GetParentIndex(j); len(parentNodes) == 0 || parentNodes[len(parentNodes)-1] != parentIndex {
+				parentNodes = append(parentNodes, parentIndex)
+			}
+		}
+
+		levelNodes = parentNodes
+	}
+
+	proofs := make([]Proof, len(indices))
+
+	for i, index := range indices {
+		j := offset + index
+
+		proof := Proof{
+			Path:      make([]TreeNode, 0, bits.Len(uint(len(t.Nodes)))),
+			Leaf:      t.Nodes[j],
+			LeafIndex: index,
+		}
+
+		for ; j > 0; j = GetParentIndex(j) {
+			proof.Path = append(proof.Path, siblings[GetSiblingIndex(j)])
+		}
+
+		proofs[i] = proof
+	}
+
+	return proofs, nil
+}
+
+// Depth returns the depth of the tree the proof was generated from, which equals the length of the path.
+func (p Proof) Depth() int {
+	return len(p.Path)
+}
+
+// ToSolidityInputs formats the proof as arguments of the on-chain Merkle verifier.
+// Every path node is returned as a 0x-prefixed 32-byte hex string. Indices is a 0x-prefixed 32-byte
+// hex string, whose k-th bit is set if the node at the k-th level of the path is a right child.
+func (p Proof) ToSolidityInputs() (path []string, indices string, err error) {
+	if p.LeafIndex < 0 {
+		return nil, "", fmt.Errorf("invalid leaf index %d", p.LeafIndex)
+	}
+
+	path = make([]string, len(p.Path))
+	for i, node := range p.Path {
+		if node.Value == nil {
+			return nil, "", fmt.Errorf("path node %d is empty", i)
+		}
+
+		path[i] = encodeUint256Hex(node.Value.Bytes32())
+	}
+
+	return path, encodeUint256Hex(uint256.NewInt(uint64(p.LeafIndex)).Bytes32()), nil
+}
+
+// MarshalHex encodes every path node as a 0x-prefixed 64-character hex string.
+// Indices is the leaf index, whose k-th bit is set if the node at the k-th level of the path is a right child.
+func (p Proof) MarshalHex() (pathHex []string, indices int, err error) {
+	pathHex = make([]string, len(p.Path))
+	for i, node := range p.Path {
+		if node.Value == nil {
+			return nil, 0, fmt.Errorf("path node %d is empty", i)
+		}
+
+		pathHex[i] = encodeUint256Hex(node.Value.Bytes32())
+	}
+
+	return pathHex, p.LeafIndex, nil
+}
+
+// UnmarshalHex decodes the path and leaf index produced by MarshalHex. The proof leaf is left unchanged.
+func (p *Proof) UnmarshalHex(pathHex []string, indices int) error {
+	if indices < 0 || indices>>len(pathHex) != 0 {
+		return fmt.Errorf("invalid indices %d for path of length %d", indices, len(pathHex))
+	}
+
+	path := make([]TreeNode, len(pathHex))
+	for i, nodeHex := range pathHex {
+		if len(nodeHex) != 66 || !strings.HasPrefix(nodeHex, "0x") {
+			return fmt.Errorf("invalid path node %d: want 0x-prefixed 64-character hex string", i)
+		}
+
+		value, err := hex.DecodeString(nodeHex[2:])
+		if err != nil {
+			return fmt.Errorf("decode path node %d: %w", i, err)
+		}
+
+		path[i] = TreeNode{Value: new(uint256.Int).SetBytes32(value)}
+	}
+
+	p.Path = path
+	p.LeafIndex = indices
+
+	return nil
+}
+
+var proofABIArguments = mustProofABIArguments()
+
+func mustProofABIArguments() abi.Arguments {
+	pathType, err := abi.NewType("bytes32[]", "", nil)
+	if err != nil {
+		panic(fmt.Sprintf("create path abi type: %s", err))
+	}
+
+	indicesType, err := abi.NewType("uint256", "", nil)
+	if err != nil {
+		panic(fmt.Sprintf("create indices abi type: %s", err))
+	}
+
+	return abi.Arguments{{Type: pathType}, {Type: indicesType}}
+}
+
+// EncodeABI encodes the proof path and leaf index as the ABI tuple (bytes32[], uint256).
+func (p Proof) EncodeABI() ([]byte, error) {
+	path := make([][32]byte, len(p.Path))
+	for i, node := range p.Path {
+		if node.Value == nil {
+			return nil, fmt.Errorf("path node %d is empty", i)
+		}
+
+		path[i] = node.Value.Bytes32()
+	}
+
+	data, err := proofABIArguments.Pack(path, big.NewInt(int64(p.LeafIndex)))
+	if err != nil {
+		return nil, fmt.Errorf("pack proof: %w", err)
+	}
+
+	return data, nil
+}
+
+// ProofFromABI decodes a proof encoded with EncodeABI. The proof leaf is not part of the encoding and is left empty.
+func ProofFromABI(data []byte) (Proof, error) {
+	values, err := proofABIArguments.Unpack(data)
+	if err != nil {
+		return Proof{}, fmt.Errorf("unpack proof: %w", err)
+	}
+
+	path, ok := values[0].([][32]byte)
+	if !ok {
+		return Proof{}, fmt.Errorf("unexpected path type %T", values[0])
+	}
+
+	indices, ok := values[1].(*big.Int)
+	if !ok {
+		return Proof{}, fmt.Errorf("unexpected indices type %T", values[1])
+	}
+
+	if !indices.IsInt64() || indices.Int64()>>len(path) != 0 {
+		return Proof{}, fmt.Errorf("invalid indices %s for path of length %d", indices, len(path))
+	}
+
+	proof := Proof{
+		LeafIndex: int(indices.Int64()),
+		Path:      make([]TreeNode, len(path)),
+	}
+
+	for i, node := range path {
+		proof.Path[i] = TreeNode{Value: new(uint256.Int).SetBytes32(node[:])}
+	}
+
+	return proof, nil
+}
+
+func encodeUint256Hex(value [32]byte) string {
+	return "0x" + hex.EncodeToString(value[:])
+}
@@ -0,0 +1,158 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
+)
+
+func TestProof_Depth(t *testing.T) {
+	for _, depth := range []int{0, 2, 5} {
+		tree, err := merkle.NewEmptyTreeWithDepth(depth)
+		require.NoError(t, err)
+
+		proof, err := tree.GetProof(0)
+		require.NoError(t, err)
+		require.Equal(t, depth, proof.Depth())
+	}
+}
+
+func TestProof_ToSolidityInputs(t *testing.T) {
+	proof := merkle.Proof{
+		Leaf:      merkle.TreeNode{Value: uint256.NewInt(1)},
+		LeafIndex: 258,
+		Path: []merkle.TreeNode{
+			{Value: uint256.NewInt(255)},
+			{Value: uint256.NewInt(4096)},
+		},
+	}
+
+	path, indices, err := proof.ToSolidityInputs()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"0x00000000000000000000000000000000000000000000000000000000000000ff",
+		"0x0000000000000000000000000000000000000000000000000000000000001000",
+	}, path)
+	require.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000102", indices)
+}
+
+func TestProof_ToSolidityInputs_invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		proof merkle.Proof
+	}{
+		{name: "empty path node", proof: merkle.Proof{LeafIndex: 1, Path: []merkle.TreeNode{{}}}},
+		{name: "negative leaf index", proof: merkle.Proof{LeafIndex: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.proof.ToSolidityInputs()
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestProof_MarshalHex(t *testing.T) {
+	tree := makeTree(t)
+
+	proof, err := tree.GetProof(2)
+	require.NoError(t, err)
+
+	pathHex, indices, err := proof.MarshalHex()
+	require.NoError(t, err)
+	require.Equal(t, 2, indices)
+	require.Len(t, pathHex, 2)
+	require.Equal(t, "0x"+strings.Repeat("0", 62)+"28", pathHex[0])
+
+	decoded := merkle.Proof{Leaf: proof.Leaf}
+	require.NoError(t, decoded.UnmarshalHex(pathHex, indices))
+	require.Equal(t, proof, decoded)
+}
+
+func TestProof_UnmarshalHex_invalid(t *testing.T) {
+	validNode := "0x" + strings.Repeat("0", 62) + "28"
+
+	tests := []struct {
+		name    string
+		pathHex []string
+		indices int
+	}{
+		{name: "missing prefix", pathHex: []string{strings.Repeat("0", 64)}},
+		{name: "short node", pathHex: []string{"0x28"}},
+		{name: "invalid hex", pathHex: []string{"0x" + strings.Repeat("z", 64)}},
+		{name: "negative indices", pathHex: []string{validNode}, indices: -1},
+		{name: "indices out of range", pathHex: []string{validNode}, indices: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var proof merkle.Proof
+			require.Error(t, proof.UnmarshalHex(tt.pathHex, tt.indices))
+		})
+	}
+}
+
+func TestProof_EncodeABI(t *testing.T) {
+	tree := makeTree(t)
+
+	proof, err := tree.GetProof(3)
+	require.NoError(t, err)
+
+	data, err := proof.EncodeABI()
+	require.NoError(t, err)
+	require.Len(t, data, 32*5)
+
+	decoded, err := merkle.ProofFromABI(data)
+	require.NoError(t, err)
+	require.Equal(t, proof.LeafIndex, decoded.LeafIndex)
+	require.True(t, areTreeNodeSlicesEqual(proof.Path, decoded.Path))
+
+	_, err = merkle.ProofFromABI(data[:64])
+	require.Error(t, err)
+}
+
+func TestTree_GetProofBatch(t *testing.T) {
+	tree := makeTree(t)
+
+	indices := []int{3, 0, 2, 3}
+
+	proofs, err := tree.GetProofBatch(indices)
+	require.NoError(t, err)
+	require.Len(t, proofs, len(indices))
+
+	for i, index := range indices {
+		expected, err := tree.GetProof(index)
+		require.NoError(t, err)
+
+		require.Equal(t, expected.LeafIndex, proofs[i].LeafIndex)
+		require.True(t, expected.Leaf.Value.Eq(proofs[i].Leaf.Value))
+		require.True(t, areTreeNodeSlicesEqual(expected.Path, proofs[i].Path), "proof paths are not equal")
+	}
+}
+
+func TestTree_GetProofBatch_outOfRange(t *testing.T) {
+	tree := makeTree(t)
+
+	_, err := tree.GetProofBatch([]int{0, 4})
+	require.Error(t, err)
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"math/big"
 	"math/bits"
+	"slices"
 
 	"github.com/holiman/uint256"
 	"github.com/iden3/go-iden3-crypto/ff"
@@ -41,19 +42,41 @@ func (n TreeNode) MarshalText() (text []byte, err error) {
 	return []byte(n.Value.Dec()), nil
 }
 
+// IsEmpty reports whether the node value equals EmptyLeafValue.
+func (n TreeNode) IsEmpty() bool {
+	return n.Value.Eq(EmptyLeafValue)
+}
+
 type Tree struct {
 	Nodes []TreeNode
+	// FirstOccupiedIndex is the index of the first leaf that was not removed by Prune.
+	FirstOccupiedIndex int
+
+	hashFunc func(input []*big.Int) (*big.Int, error)
+	history  *rootHistory
+
+	deferredHashing bool
+	dirty           dirtySet
+	hasDirtyNodes   bool
+
+	// stubLeaves is the amount of leaves of a stub tree created with NewTreeFromRootAndDepth, zero otherwise.
+	stubLeaves int
 }
 
+// TreeOption configures optional behaviour of a Tree.
+type TreeOption func(t *Tree)
+
 type Proof struct {
 	Leaf      TreeNode   `json:"leaf"`      // The Merkle tree node, which authenticity is proved by the Path.
 	LeafIndex int        `json:"leafIndex"` // Index of the Leaf in the Merkle tree.
 	Path      []TreeNode `json:"path"`
 }
 
+var fieldModulus = uint256.MustFromBig(ff.Modulus())
+
 var EmptyLeafValue = new(uint256.Int).Mod(
 	uint256.MustFromBig(new(big.Int).SetBytes(makeSeedForEmptyLeaf())),
-	uint256.MustFromBig(ff.Modulus()),
+	fieldModulus,
 )
 
 func makeSeedForEmptyLeaf() []byte {
@@ -65,16 +88,100 @@ func makeSeedForEmptyLeaf() []byte {
 
 const TreeDepth = 32
 
+// TreeDepthForLeaves returns the minimum depth d such that a tree of depth d holds at least n leaves,
+// i.e. 2^d >= n. It returns 1 for n <= 2. The maximum practical depth of the SDK is TreeDepth,
+// so an error is returned for n > 2^TreeDepth and for negative n.
+func TreeDepthForLeaves(n int) (int, error) {
+	if n < 0 {
+		return 0, fmt.Errorf("negative leaves amount %d", n)
+	}
+
+	if uint64(n) > 1<<TreeDepth {
+		return 0, fmt.Errorf("leaves amount %d exceeds maximum %d", n, uint64(1)<<TreeDepth)
+	}
+
+	if n <= 2 {
+		return 1, nil
+	}
+
+	return bits.Len(uint(n - 1)), nil
+}
+
+// EmptySubtreeHashes holds the root hash of an all-empty subtree for every height up to TreeDepth.
+// Index 0 is EmptyLeafValue and index k is HashFunc(EmptySubtreeHashes[k-1], EmptySubtreeHashes[k-1]).
+var EmptySubtreeHashes = makeEmptySubtreeHashes()
+
+func makeEmptySubtreeHashes() [TreeDepth + 1]TreeNode {
+	var res [TreeDepth + 1]TreeNode
+	res[0] = TreeNode{Value: EmptyLeafValue}
+
+	for k := 1; k <= TreeDepth; k++ {
+		node, err := computeNodeHash(HashFunc, res[k-1], res[k-1])
+		if err != nil {
+			panic(fmt.Sprintf("compute empty subtree hash at height %d: %s", k, err))
+		}
+
+		res[k] = node
+	}
+
+	return res
+}
+
 func HashFunc(input []*big.Int) (*big.Int, error) {
 	return poseidon.Hash(input)
 }
 
-func NewEmptyTree(depth int, leafValue *uint256.Int) (*Tree, error) {
+// WithHashFunc replaces HashFunc with a custom hash function for all hashing performed by the tree.
+// It is intended for testing, trees built with a custom hash function are incompatible with on-chain registries.
+func WithHashFunc(f func(input []*big.Int) (*big.Int, error)) TreeOption {
+	return func(t *Tree) {
+		t.hashFunc = f
+	}
+}
+
+// TreeConfig holds the configuration of a new tree.
+type TreeConfig struct {
+	// Depth is the amount of levels below the root, the tree holds 2^Depth leaves.
+	Depth int
+	// EmptyLeafValue is the value of empty leaves. Package level EmptyLeafValue is used if nil.
+	EmptyLeafValue *uint256.Int
+	// HashFunc is the hash function used to compute internal nodes. Package level HashFunc is used if nil.
+	HashFunc func(input []*big.Int) (*big.Int, error)
+}
+
+// NewEmptyTree creates a tree of the given depth with all leaves set to leafValue.
+// It is kept for compatibility, new code should prefer NewTreeFromConfig.
+func NewEmptyTree(depth int, leafValue *uint256.Int, opts ...TreeOption) (*Tree, error) {
+	return NewTreeFromConfig(TreeConfig{Depth: depth, EmptyLeafValue: leafValue}, opts...)
+}
+
+// NewEmptyTreeWithDepth creates a tree of the given depth with all leaves set to EmptyLeafValue.
+func NewEmptyTreeWithDepth(depth int, opts ...TreeOption) (*Tree, error) {
+	return NewTreeFromConfig(TreeConfig{Depth: depth}, opts...)
+}
+
+// NewTreeFromConfig creates a tree with all leaves set to the configured empty leaf value.
+func NewTreeFromConfig(cfg TreeConfig, opts ...TreeOption) (*Tree, error) {
+	depth := cfg.Depth
 	if depth < 0 {
 		return nil, fmt.Errorf("invalid tree depth")
 	}
 
+	leafValue := cfg.EmptyLeafValue
+	if leafValue == nil {
+		leafValue = EmptyLeafValue
+	}
+
+	tree := &Tree{
+		hashFunc: cfg.HashFunc,
+	}
+
+	for _, opt := range opts {
+		opt(tree)
+	}
+
 	nodes := make([]TreeNode, 1<<(depth+1)-1)
+	tree.Nodes = nodes
 
 	firstNodeIndex := len(nodes)
 
@@ -86,7 +193,7 @@ func NewEmptyTree(depth int, leafValue *uint256.Int) (*Tree, error) {
 		}
 
 		if firstNodeIndex > 0 {
-			node, err := computeChildrenHash(firstNodeIndex-1, nodes)
+			node, err := tree.computeChildrenHash(firstNodeIndex - 1)
 			if err != nil {
 				return nil, fmt.Errorf("compute hash: %w", err)
 			}
@@ -95,45 +202,388 @@ func NewEmptyTree(depth int, leafValue *uint256.Int) (*Tree, error) {
 		}
 	}
 
-	return &Tree{
-		Nodes: nodes,
-	}, nil
+	tree.initState()
+
+	return tree, nil
+}
+
+// NewTreeFromSnapshot creates a tree from a flat node array, e.g. a copy of Tree.Nodes stored in a database.
+// The amount of nodes must be 2^k - 1 for some k, and the nodes must be consistent.
+func NewTreeFromSnapshot(nodes []TreeNode, opts ...TreeOption) (*Tree, error) {
+	if len(nodes) == 0 || (len(nodes)+1)&len(nodes) != 0 {
+		return nil, fmt.Errorf("invalid amount of nodes %d, want 2^k - 1", len(nodes))
+	}
+
+	if depth := bits.Len(uint(len(nodes))) - 1; depth > TreeDepth {
+		return nil, fmt.Errorf("tree depth %d exceeds maximum %d", depth, TreeDepth)
+	}
+
+	tree := &Tree{
+		Nodes: slices.Clone(nodes),
+	}
+
+	for _, opt := range opts {
+		opt(tree)
+	}
+
+	if err := tree.VerifyConsistency(); err != nil {
+		return nil, fmt.Errorf("verify consistency: %w", err)
+	}
+
+	tree.initState()
+
+	return tree, nil
+}
+
+// NewTreeFromRootAndDepth creates a stub tree that only stores its root, e.g. to verify proofs with VerifyProof.
+// GetLeavesAmount reports the amount of leaves of the given depth, but methods that need other nodes,
+// such as SetLeaf, GetLeaf and GetProof, return an error.
+func NewTreeFromRootAndDepth(root TreeNode, depth int, opts ...TreeOption) (*Tree, error) {
+	if depth < 0 || depth > TreeDepth {
+		return nil, fmt.Errorf("invalid tree depth")
+	}
+
+	if root.Value == nil {
+		return nil, fmt.Errorf("root is empty")
+	}
+
+	tree := &Tree{
+		Nodes:      []TreeNode{root},
+		stubLeaves: 1 << depth,
+	}
+
+	for _, opt := range opts {
+		opt(tree)
+	}
+
+	tree.initState()
+
+	return tree, nil
+}
+
+// checkNodes returns an error if the tree is a stub tree that only stores its root.
+func (t *Tree) checkNodes() error {
+	if t.stubLeaves > 0 {
+		return fmt.Errorf("stub tree only stores its root")
+	}
+
+	return nil
+}
+
+// initState initializes the optional tree state after the nodes are built.
+func (t *Tree) initState() {
+	if t.deferredHashing {
+		t.dirty = newDirtySet(len(t.Nodes))
+	}
+
+	t.history.start(t.Nodes[0])
 }
 
 func (t *Tree) SetLeaf(i int, val TreeNode) error {
+	if err := t.checkNodes(); err != nil {
+		return err
+	}
+
 	leavesAmount := t.GetLeavesAmount()
 
 	if i >= leavesAmount || i < 0 {
 		return fmt.Errorf("invalid leaf index")
 	}
 
+	if val.Value == nil {
+		return fmt.Errorf("leaf value is empty")
+	}
+
+	if !val.Value.Lt(fieldModulus) {
+		return fmt.Errorf("leaf value is not in the field")
+	}
+
 	j := len(t.Nodes) - leavesAmount + i
+
+	if t.dirty != nil {
+		t.Nodes[j] = val
+		t.markDirty(j)
+
+		if t.history != nil {
+			if err := t.Flush(); err != nil {
+				return err
+			}
+
+			t.history.record(i, val, t.Nodes[0])
+		}
+
+		return nil
+	}
+
 	t.Nodes[j] = val
 
 	for j := GetParentIndex(j); j > 0; j = GetParentIndex(j) {
 		var err error
-		t.Nodes[j], err = computeChildrenHash(j, t.Nodes)
+		t.Nodes[j], err = t.computeChildrenHash(j)
 		if err != nil {
 			return fmt.Errorf("compute hash: %w", err)
 		}
 	}
 
 	var err error
-	t.Nodes[0], err = computeChildrenHash(0, t.Nodes)
+	t.Nodes[0], err = t.computeChildrenHash(0)
 	if err != nil {
 		return fmt.Errorf("compute hash: %w", err)
 	}
 
+	t.history.record(i, val, t.Nodes[0])
+
+	return nil
+}
+
+// GetLeaf returns the leaf at the given index.
+func (t *Tree) GetLeaf(i int) (TreeNode, error) {
+	if err := t.checkNodes(); err != nil {
+		return TreeNode{}, err
+	}
+
+	leavesAmount := t.GetLeavesAmount()
+
+	if i >= leavesAmount || i < 0 {
+		return TreeNode{}, fmt.Errorf("invalid leaf index")
+	}
+
+	return t.Nodes[len(t.Nodes)-leavesAmount+i], nil
+}
+
+// HashAtIndex returns the node at the given flat array index, where the root is at index 0.
+// Pending deferred changes are flushed first.
+func (t *Tree) HashAtIndex(i int) (TreeNode, error) {
+	if i >= len(t.Nodes) || i < 0 {
+		return TreeNode{}, fmt.Errorf("invalid node index")
+	}
+
+	if err := t.Flush(); err != nil {
+		return TreeNode{}, err
+	}
+
+	return t.Nodes[i], nil
+}
+
+// OccupiedLeafIndices returns the ascending indices of all leaves that are not empty.
+// A stub tree created with NewTreeFromRootAndDepth has no known occupied leaves.
+func (t *Tree) OccupiedLeafIndices() []int {
+	if t.stubLeaves > 0 {
+		return nil
+	}
+
+	leavesAmount := t.GetLeavesAmount()
+	offset := len(t.Nodes) - leavesAmount
+
+	var indices []int
+	for i, leaf := range t.Nodes[offset:] {
+		if !leaf.IsEmpty() {
+			indices = append(indices, i)
+		}
+	}
+
+	return indices
+}
+
+// ForEachLeaf calls fn for every leaf, empty or not, in ascending index order until fn returns false.
+func (t *Tree) ForEachLeaf(fn func(index int, node TreeNode) bool) error {
+	if err := t.checkNodes(); err != nil {
+		return err
+	}
+
+	if fn == nil {
+		return fmt.Errorf("leaf callback is nil")
+	}
+
+	leavesAmount := t.GetLeavesAmount()
+	offset := len(t.Nodes) - leavesAmount
+
+	for i, leaf := range t.Nodes[offset:] {
+		if !fn(i, leaf) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// SetLeafIfEmpty sets the leaf value only if the leaf at the given index is empty.
+// It reports whether the leaf was written.
+func (t *Tree) SetLeafIfEmpty(i int, val TreeNode) (bool, error) {
+	leaf, err := t.GetLeaf(i)
+	if err != nil {
+		return false, err
+	}
+
+	if !leaf.IsEmpty() {
+		return false, nil
+	}
+
+	if err := t.SetLeaf(i, val); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RootAfterSet computes the root the tree would have after setting the leaf at index i to val.
+// Only the path from the leaf to the root is hashed, the tree itself is not modified.
+func (t *Tree) RootAfterSet(i int, val TreeNode) (TreeNode, error) {
+	if err := t.checkNodes(); err != nil {
+		return TreeNode{}, err
+	}
+
+	leavesAmount := t.GetLeavesAmount()
+
+	if i >= leavesAmount || i < 0 {
+		return TreeNode{}, fmt.Errorf("invalid leaf index")
+	}
+
+	if err := t.Flush(); err != nil {
+		return TreeNode{}, err
+	}
+
+	node := val
+
+	for j := len(t.Nodes) - leavesAmount + i; j > 0; j = GetParentIndex(j) {
+		sibling := t.Nodes[GetSiblingIndex(j)]
+
+		var err error
+		if IsRightChild(j) {
+			node, err = t.computeNodeHash(sibling, node)
+		} else {
+			node, err = t.computeNodeHash(node, sibling)
+		}
+		if err != nil {
+			return TreeNode{}, fmt.Errorf("compute hash: %w", err)
+		}
+	}
+
+	return node, nil
+}
+
+// MigrateLeaf moves the leaf value from srcIndex to dstIndex and clears srcIndex to EmptyLeafValue.
+// Ancestor hashes of both leaves are updated in a single bottom-up pass. It fails if the destination leaf is not empty.
+func (t *Tree) MigrateLeaf(srcIndex, dstIndex int) error {
+	if err := t.checkNodes(); err != nil {
+		return err
+	}
+
+	leavesAmount := t.GetLeavesAmount()
+
+	if srcIndex >= leavesAmount || srcIndex < 0 || dstIndex >= leavesAmount || dstIndex < 0 {
+		return fmt.Errorf("invalid leaf index")
+	}
+
+	if srcIndex == dstIndex {
+		return fmt.Errorf("source and destination leaf indices are equal")
+	}
+
+	offset := len(t.Nodes) - leavesAmount
+	src, dst := offset+srcIndex, offset+dstIndex
+
+	if !t.Nodes[dst].IsEmpty() {
+		return fmt.Errorf("destination leaf %d is not empty", dstIndex)
+	}
+
+	val := t.Nodes[src]
+	t.Nodes[dst] = val
+	t.Nodes[src] = TreeNode{Value: EmptyLeafValue}
+
+	if t.dirty != nil {
+		t.markDirty(src)
+		t.markDirty(dst)
+
+		if t.history == nil {
+			return nil
+		}
+
+		if err := t.Flush(); err != nil {
+			return err
+		}
+	} else {
+		for src > 0 {
+			src, dst = GetParentIndex(src), GetParentIndex(dst)
+
+			var err error
+			t.Nodes[src], err = t.computeChildrenHash(src)
+			if err != nil {
+				return fmt.Errorf("compute hash: %w", err)
+			}
+
+			if dst != src {
+				t.Nodes[dst], err = t.computeChildrenHash(dst)
+				if err != nil {
+					return fmt.Errorf("compute hash: %w", err)
+				}
+			}
+		}
+	}
+
+	t.history.record(srcIndex, TreeNode{Value: EmptyLeafValue}, t.Nodes[0])
+	t.history.record(dstIndex, val, t.Nodes[0])
+
+	return nil
+}
+
+// Prune empties all leaves before keepFromLeafIndex, recomputes their ancestors and advances FirstOccupiedIndex.
+// keepFromLeafIndex must be a power of two not exceeding the amount of leaves, so the pruned leaves form
+// a complete subtree. Pruning below FirstOccupiedIndex only empties leaves that were set since then.
+func (t *Tree) Prune(keepFromLeafIndex int) error {
+	if err := t.checkNodes(); err != nil {
+		return err
+	}
+
+	leavesAmount := t.GetLeavesAmount()
+
+	if keepFromLeafIndex <= 0 || keepFromLeafIndex > leavesAmount || keepFromLeafIndex&(keepFromLeafIndex-1) != 0 {
+		return fmt.Errorf("leaf index %d is not a subtree boundary", keepFromLeafIndex)
+	}
+
+	if err := t.Flush(); err != nil {
+		return err
+	}
+
+	emptyLeaf := TreeNode{Value: EmptyLeafValue}
+
+	first, last := len(t.Nodes)-leavesAmount, len(t.Nodes)-leavesAmount+keepFromLeafIndex
+	for j := first; j < last; j++ {
+		t.Nodes[j] = emptyLeaf
+	}
+
+	for first > 0 {
+		first, last = GetParentIndex(first), GetParentIndex(last-1)+1
+
+		for j := first; j < last; j++ {
+			var err error
+			t.Nodes[j], err = t.computeChildrenHash(j)
+			if err != nil {
+				return fmt.Errorf("compute hash: %w", err)
+			}
+		}
+	}
+
+	t.FirstOccupiedIndex = max(t.FirstOccupiedIndex, keepFromLeafIndex)
+	t.history.recordPrune(keepFromLeafIndex, emptyLeaf, t.Nodes[0])
+
 	return nil
 }
 
 func (t *Tree) GetProof(i int) (Proof, error) {
+	if err := t.checkNodes(); err != nil {
+		return Proof{}, err
+	}
+
 	leavesAmount := t.GetLeavesAmount()
 
 	if i >= leavesAmount || i < 0 {
 		return Proof{}, fmt.Errorf("invalid leaf index")
 	}
 
+	if err := t.Flush(); err != nil {
+		return Proof{}, err
+	}
+
 	j := len(t.Nodes) - leavesAmount + i
 
 	proof := Proof{
@@ -152,14 +602,227 @@ func (t *Tree) GetProof(i int) (Proof, error) {
 	return proof, nil
 }
 
+// GetPathToLeaf returns the flat array indices of the nodes on the path from the root to the given leaf,
+// starting with the root at index 0 and ending with the leaf node.
+func (t *Tree) GetPathToLeaf(leafIndex int) ([]int, error) {
+	if err := t.checkNodes(); err != nil {
+		return nil, err
+	}
+
+	leavesAmount := t.GetLeavesAmount()
+
+	if leafIndex >= leavesAmount || leafIndex < 0 {
+		return nil, fmt.Errorf("invalid leaf index")
+	}
+
+	j := len(t.Nodes) - leavesAmount + leafIndex
+	path := make([]int, bits.Len(uint(leavesAmount)))
+
+	for level := len(path) - 1; level >= 0; level-- {
+		path[level] = j
+		j = GetParentIndex(j)
+	}
+
+	return path, nil
+}
+
+// CopySubtree returns an independent tree holding a copy of the subtree rooted at the given flat array index,
+// so its root equals t.Nodes[rootIndex]. The copy uses the hash function and hashing mode of the tree,
+// but not its root history. Pending deferred changes are flushed first.
+func (t *Tree) CopySubtree(rootIndex int) (*Tree, error) {
+	if err := t.checkNodes(); err != nil {
+		return nil, err
+	}
+
+	leavesAmount := t.GetLeavesAmount()
+
+	if rootIndex >= len(t.Nodes) || rootIndex < 0 {
+		return nil, fmt.Errorf("invalid node index")
+	}
+
+	if rootIndex >= len(t.Nodes)-leavesAmount {
+		return nil, fmt.Errorf("node %d is a leaf", rootIndex)
+	}
+
+	if err := t.Flush(); err != nil {
+		return nil, err
+	}
+
+	level := bits.Len(uint(rootIndex+1)) - 1
+	depth := bits.Len(uint(leavesAmount)) - 1 - level
+	nodes := make([]TreeNode, 0, 1<<(depth+1)-1)
+
+	for k, first := 0, rootIndex; k <= depth; k, first = k+1, 2*first+1 {
+		nodes = append(nodes, t.Nodes[first:first+1<<k]...)
+	}
+
+	subtree := &Tree{
+		Nodes:           nodes,
+		hashFunc:        t.hashFunc,
+		deferredHashing: t.deferredHashing,
+	}
+	subtree.initState()
+
+	return subtree, nil
+}
+
+// Root returns the root of the tree. Pending deferred changes are flushed first,
+// call Flush explicitly to handle hashing errors.
 func (t *Tree) Root() TreeNode {
+	_ = t.Flush()
+
 	return t.Nodes[0]
 }
 
+// StateHash returns hash(root, occupied leaves amount, depth) computed with the tree hash function,
+// a compact identifier of the tree state that changes whenever a leaf changes.
+// It is suited as a cache key for data derived from the tree.
+func (t *Tree) StateHash() (TreeNode, error) {
+	if err := t.checkNodes(); err != nil {
+		return TreeNode{}, err
+	}
+
+	if err := t.Flush(); err != nil {
+		return TreeNode{}, err
+	}
+
+	stats := t.Stats()
+
+	val, err := t.hash([]*big.Int{
+		t.Nodes[0].Value.ToBig(),
+		big.NewInt(int64(stats.OccupiedLeaves)),
+		big.NewInt(int64(stats.Depth)),
+	})
+	if err != nil {
+		return TreeNode{}, fmt.Errorf("compute state hash: %w", err)
+	}
+
+	convertedVal, isOverflow := uint256.FromBig(val)
+	if isOverflow {
+		return TreeNode{}, fmt.Errorf("invalid state hash")
+	}
+
+	return TreeNode{Value: convertedVal}, nil
+}
+
+// VerifyProof checks that the proof leads from its leaf to the root of the tree.
+// The proof depth must match the tree depth.
+func (t *Tree) VerifyProof(proof Proof) error {
+	leavesAmount := t.GetLeavesAmount()
+
+	if depth := bits.Len(uint(leavesAmount)) - 1; proof.Depth() != depth {
+		return fmt.Errorf("proof depth %d does not match tree depth %d", proof.Depth(), depth)
+	}
+
+	if proof.LeafIndex >= leavesAmount || proof.LeafIndex < 0 {
+		return fmt.Errorf("invalid leaf index")
+	}
+
+	if proof.Leaf.Value == nil {
+		return fmt.Errorf("proof leaf is empty")
+	}
+
+	node := proof.Leaf
+	for level, sibling := range proof.Path {
+		if sibling.Value == nil {
+			return fmt.Errorf("proof node at level %d is empty", level)
+		}
+
+		left, right := node, sibling
+		if proof.LeafIndex>>level&1 == 1 {
+			left, right = sibling, node
+		}
+
+		var err error
+		node, err = t.computeNodeHash(left, right)
+		if err != nil {
+			return fmt.Errorf("compute hash: %w", err)
+		}
+	}
+
+	if !node.Value.Eq(t.Root().Value) {
+		return fmt.Errorf("proof does not match the tree root")
+	}
+
+	return nil
+}
+
+// VerifyConsistency checks that every internal node equals the hash of its children.
+// An error identifying the first inconsistent node index is returned otherwise.
+func (t *Tree) VerifyConsistency() error {
+	if err := t.checkNodes(); err != nil {
+		return err
+	}
+
+	if err := t.Flush(); err != nil {
+		return err
+	}
+
+	for i, node := range t.Nodes {
+		if node.Value == nil {
+			return fmt.Errorf("node %d is empty", i)
+		}
+	}
+
+	for i := 0; i < len(t.Nodes)-t.GetLeavesAmount(); i++ {
+		expected, err := t.computeChildrenHash(i)
+		if err != nil {
+			return fmt.Errorf("compute hash of node %d: %w", i, err)
+		}
+
+		if !expected.Value.Eq(t.Nodes[i].Value) {
+			return fmt.Errorf("node %d does not match the hash of its children", i)
+		}
+	}
+
+	return nil
+}
+
 func (t *Tree) GetLeavesAmount() int {
+	if t.stubLeaves > 0 {
+		return t.stubLeaves
+	}
+
 	return (len(t.Nodes) + 1) / 2
 }
 
+// Size returns the number of nodes in the tree, including inner nodes and leaves.
+// The number of leaves the tree can hold is returned by GetLeavesAmount.
+// A stub tree created with NewTreeFromRootAndDepth only stores its root.
+func (t *Tree) Size() int {
+	return len(t.Nodes)
+}
+
+// TreeStats holds basic statistics about the tree.
+type TreeStats struct {
+	Depth          int
+	TotalLeaves    int
+	OccupiedLeaves int
+	FillRatio      float64
+}
+
+// Stats computes statistics about the tree by iterating over its leaves once.
+// A stub tree created with NewTreeFromRootAndDepth reports no occupied leaves.
+func (t *Tree) Stats() TreeStats {
+	leavesAmount := t.GetLeavesAmount()
+
+	occupiedLeaves := 0
+	if t.stubLeaves == 0 {
+		for _, leaf := range t.Nodes[len(t.Nodes)-leavesAmount:] {
+			if !leaf.IsEmpty() {
+				occupiedLeaves++
+			}
+		}
+	}
+
+	return TreeStats{
+		Depth:          bits.Len(uint(leavesAmount)) - 1,
+		TotalLeaves:    leavesAmount,
+		OccupiedLeaves: occupiedLeaves,
+		FillRatio:      float64(occupiedLeaves) / float64(leavesAmount),
+	}
+}
+
 func GetParentIndex(i int) int {
 	return (i - 1) / 2
 }
@@ -172,16 +835,46 @@ func GetSiblingIndex(i int) int {
 	return i + 1
 }
 
+// GetSiblingPath returns the flat array indices of the siblings of the given leaf in a tree of the given depth,
+// ordered from the leaf level up to, but not including, the root. It returns nil if the leaf index is out of range.
+func GetSiblingPath(leafIndex int, depth int) []int {
+	if depth < 0 || leafIndex < 0 || leafIndex >= 1<<depth {
+		return nil
+	}
+
+	path := make([]int, 0, depth)
+	for j := 1<<depth - 1 + leafIndex; j > 0; j = GetParentIndex(j) {
+		path = append(path, GetSiblingIndex(j))
+	}
+
+	return path
+}
+
 func IsRightChild(i int) bool {
 	return i%2 == 0
 }
 
-func computeChildrenHash(i int, nodes []TreeNode) (TreeNode, error) {
-	return computeNodeHash(getChildrenOf(i, nodes))
+func (t *Tree) computeChildrenHash(i int) (TreeNode, error) {
+	left, right := getChildrenOf(i, t.Nodes)
+
+	return t.computeNodeHash(left, right)
+}
+
+func (t *Tree) computeNodeHash(left, right TreeNode) (TreeNode, error) {
+	return computeNodeHash(t.hash, left, right)
+}
+
+// hash hashes the input with the tree hash function, see WithHashFunc.
+func (t *Tree) hash(input []*big.Int) (*big.Int, error) {
+	if t.hashFunc == nil {
+		return HashFunc(input)
+	}
+
+	return t.hashFunc(input)
 }
 
-func computeNodeHash(left, right TreeNode) (TreeNode, error) {
-	val, err := HashFunc([]*big.Int{left.Value.ToBig(), right.Value.ToBig()})
+func computeNodeHash(hashFunc func(input []*big.Int) (*big.Int, error), left, right TreeNode) (TreeNode, error) {
+	val, err := hashFunc([]*big.Int{left.Value.ToBig(), right.Value.ToBig()})
 	if err != nil {
 		return TreeNode{}, err
 	}
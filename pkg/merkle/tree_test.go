@@ -16,6 +16,8 @@
 package merkle_test
 
 import (
+	"math/big"
+	"slices"
 	"testing"
 
 	"github.com/holiman/uint256"
@@ -89,6 +91,181 @@ func TestTree_SetLeaf_outOfRange(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestTree_SetLeaf_invalidValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value *uint256.Int
+	}{
+		{name: "empty", value: nil},
+		{name: "not in field", value: new(uint256.Int).SetAllOne()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := makeTree(t)
+			root := tree.Root()
+
+			require.Error(t, tree.SetLeaf(0, merkle.TreeNode{Value: tt.value}))
+			require.Equal(t, root, tree.Root())
+
+			leaf, err := tree.GetLeaf(0)
+			require.NoError(t, err)
+			require.Equal(t, uint256.NewInt(10), leaf.Value)
+		})
+	}
+}
+
+func TestTree_GetLeaf(t *testing.T) {
+	tree := makeTree(t)
+
+	for i, expected := range []uint64{10, 20, 30, 40} {
+		leaf, err := tree.GetLeaf(i)
+		require.NoError(t, err)
+		require.Equal(t, uint256.NewInt(expected), leaf.Value)
+	}
+
+	_, err := tree.GetLeaf(4)
+	require.Error(t, err)
+
+	_, err = tree.GetLeaf(-1)
+	require.Error(t, err)
+}
+
+func TestTree_HashAtIndex(t *testing.T) {
+	tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue, merkle.WithDeferredHashing())
+	require.NoError(t, err)
+	require.NoError(t, tree.SetLeaf(1, merkle.TreeNode{Value: uint256.NewInt(20)}))
+
+	expected := makeTree(t)
+	require.NoError(t, expected.SetLeaf(0, merkle.TreeNode{Value: merkle.EmptyLeafValue}))
+	require.NoError(t, expected.SetLeaf(2, merkle.TreeNode{Value: merkle.EmptyLeafValue}))
+	require.NoError(t, expected.SetLeaf(3, merkle.TreeNode{Value: merkle.EmptyLeafValue}))
+
+	for i := range expected.Nodes {
+		node, err := tree.HashAtIndex(i)
+		require.NoError(t, err)
+		require.Equal(t, expected.Nodes[i], node)
+	}
+
+	_, err = tree.HashAtIndex(len(expected.Nodes))
+	require.Error(t, err)
+
+	_, err = tree.HashAtIndex(-1)
+	require.Error(t, err)
+}
+
+func TestTree_OccupiedLeafIndices(t *testing.T) {
+	tree, err := merkle.NewEmptyTree(3, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+	require.Empty(t, tree.OccupiedLeafIndices())
+
+	require.NoError(t, tree.SetLeaf(5, merkle.TreeNode{Value: uint256.NewInt(20)}))
+	require.NoError(t, tree.SetLeaf(0, merkle.TreeNode{Value: uint256.NewInt(10)}))
+	require.NoError(t, tree.SetLeaf(7, merkle.TreeNode{Value: uint256.NewInt(30)}))
+
+	require.Equal(t, []int{0, 5, 7}, tree.OccupiedLeafIndices())
+}
+
+func TestTree_ForEachLeaf(t *testing.T) {
+	tree := makeTree(t)
+
+	var values []uint64
+	require.NoError(t, tree.ForEachLeaf(func(index int, node merkle.TreeNode) bool {
+		require.Equal(t, len(values), index)
+		values = append(values, node.Value.Uint64())
+		return true
+	}))
+	require.Equal(t, []uint64{10, 20, 30, 40}, values)
+
+	visited := 0
+	require.NoError(t, tree.ForEachLeaf(func(index int, node merkle.TreeNode) bool {
+		visited++
+		return node.Value.Uint64() != 20
+	}))
+	require.Equal(t, 2, visited)
+
+	require.Error(t, tree.ForEachLeaf(nil))
+}
+
+func TestTreeNode_IsEmpty(t *testing.T) {
+	require.True(t, merkle.TreeNode{Value: merkle.EmptyLeafValue.Clone()}.IsEmpty())
+	require.False(t, merkle.TreeNode{Value: uint256.NewInt(10)}.IsEmpty())
+	require.False(t, merkle.EmptySubtreeHashes[1].IsEmpty())
+}
+
+func TestTree_SetLeafIfEmpty(t *testing.T) {
+	tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+
+	written, err := tree.SetLeafIfEmpty(1, merkle.TreeNode{Value: uint256.NewInt(42)})
+	require.NoError(t, err)
+	require.True(t, written)
+
+	written, err = tree.SetLeafIfEmpty(1, merkle.TreeNode{Value: uint256.NewInt(43)})
+	require.NoError(t, err)
+	require.False(t, written)
+	require.True(t, tree.Nodes[4].Value.Eq(uint256.NewInt(42)))
+
+	_, err = tree.SetLeafIfEmpty(4, merkle.TreeNode{Value: uint256.NewInt(42)})
+	require.Error(t, err)
+}
+
+func TestTree_RootAfterSet(t *testing.T) {
+	for i := 0; i < 4; i++ {
+		tree := makeTree(t)
+		nodesBefore := slices.Clone(tree.Nodes)
+
+		root, err := tree.RootAfterSet(i, merkle.TreeNode{Value: uint256.NewInt(50)})
+		require.NoError(t, err)
+		require.True(t, areTreeNodeSlicesEqual(nodesBefore, tree.Nodes))
+
+		require.NoError(t, tree.SetLeaf(i, merkle.TreeNode{Value: uint256.NewInt(50)}))
+		require.True(t, root.Value.Eq(tree.Root().Value))
+	}
+
+	_, err := makeTree(t).RootAfterSet(4, merkle.TreeNode{Value: uint256.NewInt(50)})
+	require.Error(t, err)
+}
+
+func TestTree_MigrateLeaf(t *testing.T) {
+	for _, opts := range [][]merkle.TreeOption{nil, {merkle.WithDeferredHashing()}} {
+		tree, err := merkle.NewEmptyTree(3, merkle.EmptyLeafValue, opts...)
+		require.NoError(t, err)
+		require.NoError(t, tree.SetLeaf(1, merkle.TreeNode{Value: uint256.NewInt(10)}))
+		require.NoError(t, tree.SetLeaf(2, merkle.TreeNode{Value: uint256.NewInt(20)}))
+
+		expected, err := merkle.NewEmptyTree(3, merkle.EmptyLeafValue)
+		require.NoError(t, err)
+		require.NoError(t, expected.SetLeaf(6, merkle.TreeNode{Value: uint256.NewInt(10)}))
+		require.NoError(t, expected.SetLeaf(2, merkle.TreeNode{Value: uint256.NewInt(20)}))
+
+		require.NoError(t, tree.MigrateLeaf(1, 6))
+		require.True(t, expected.Root().Value.Eq(tree.Root().Value))
+		require.True(t, areTreeNodeSlicesEqual(expected.Nodes, tree.Nodes))
+	}
+}
+
+func TestTree_MigrateLeaf_invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		src, dst int
+	}{
+		{name: "source out of range", src: 4, dst: 0},
+		{name: "destination out of range", src: 0, dst: -1},
+		{name: "same index", src: 1, dst: 1},
+		{name: "destination not empty", src: 0, dst: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := makeTree(t)
+
+			require.Error(t, tree.MigrateLeaf(tt.src, tt.dst))
+			require.True(t, areTreeNodeSlicesEqual(makeTree(t).Nodes, tree.Nodes))
+		})
+	}
+}
+
 func TestTree_GetProof(t *testing.T) {
 	tree := makeTree(t)
 
@@ -127,6 +304,229 @@ func TestTree_Root_multipleLeaves(t *testing.T) {
 	require.True(t, expectedValue.Eq(root.Value), "invalid merkle root")
 }
 
+func TestTree_VerifyConsistency(t *testing.T) {
+	tree := makeTree(t)
+	require.NoError(t, tree.VerifyConsistency())
+
+	tree.Nodes[5] = merkle.TreeNode{Value: uint256.NewInt(1)}
+	require.ErrorContains(t, tree.VerifyConsistency(), "node 2 ")
+
+	tree.Nodes[0] = merkle.TreeNode{Value: uint256.NewInt(1)}
+	require.ErrorContains(t, tree.VerifyConsistency(), "node 0 ")
+
+	tree.Nodes[3] = merkle.TreeNode{}
+	require.ErrorContains(t, tree.VerifyConsistency(), "node 3 is empty")
+}
+
+func TestNewTreeFromSnapshot(t *testing.T) {
+	expected := makeTree(t)
+
+	tree, err := merkle.NewTreeFromSnapshot(expected.Nodes)
+	require.NoError(t, err)
+	require.True(t, areTreeNodeSlicesEqual(expected.Nodes, tree.Nodes))
+
+	require.NoError(t, tree.SetLeaf(0, merkle.TreeNode{Value: uint256.NewInt(50)}))
+	require.True(t, expected.Nodes[3].Value.Eq(uint256.NewInt(10)))
+}
+
+func TestNewTreeFromSnapshot_invalid(t *testing.T) {
+	inconsistent := slices.Clone(makeTree(t).Nodes)
+	inconsistent[4] = merkle.TreeNode{Value: uint256.NewInt(1)}
+
+	tests := []struct {
+		name  string
+		nodes []merkle.TreeNode
+	}{
+		{name: "no nodes", nodes: nil},
+		{name: "invalid amount of nodes", nodes: makeTree(t).Nodes[:6]},
+		{name: "inconsistent nodes", nodes: inconsistent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := merkle.NewTreeFromSnapshot(tt.nodes)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestEmptySubtreeHashes(t *testing.T) {
+	require.True(t, merkle.EmptySubtreeHashes[0].Value.Eq(merkle.EmptyLeafValue))
+
+	for _, depth := range []int{1, 3, 7} {
+		tree, err := merkle.NewEmptyTree(depth, merkle.EmptyLeafValue)
+		require.NoError(t, err)
+		require.True(t, merkle.EmptySubtreeHashes[depth].Value.Eq(tree.Root().Value), "invalid hash at depth %d", depth)
+	}
+}
+
+func TestTree_CopySubtree(t *testing.T) {
+	tree, err := merkle.NewEmptyTree(3, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+
+	for i := 0; i < tree.GetLeavesAmount(); i++ {
+		require.NoError(t, tree.SetLeaf(i, merkle.TreeNode{Value: uint256.NewInt(uint64(10 * (i + 1)))}))
+	}
+
+	tests := []struct {
+		name       string
+		rootIndex  int
+		wantDepth  int
+		wantLeaves []uint64
+	}{
+		{name: "whole tree", rootIndex: 0, wantDepth: 3, wantLeaves: []uint64{10, 20, 30, 40, 50, 60, 70, 80}},
+		{name: "right half", rootIndex: 2, wantDepth: 2, wantLeaves: []uint64{50, 60, 70, 80}},
+		{name: "second quarter", rootIndex: 4, wantDepth: 1, wantLeaves: []uint64{30, 40}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subtree, err := tree.CopySubtree(tt.rootIndex)
+			require.NoError(t, err)
+
+			require.Equal(t, tree.Nodes[tt.rootIndex], subtree.Root())
+			require.Equal(t, tt.wantDepth, subtree.Stats().Depth)
+			require.NoError(t, subtree.VerifyConsistency())
+
+			var leaves []uint64
+			require.NoError(t, subtree.ForEachLeaf(func(_ int, node merkle.TreeNode) bool {
+				leaves = append(leaves, node.Value.Uint64())
+				return true
+			}))
+			require.Equal(t, tt.wantLeaves, leaves)
+		})
+	}
+
+	t.Run("independent copy", func(t *testing.T) {
+		subtree, err := tree.CopySubtree(1)
+		require.NoError(t, err)
+
+		root := tree.Root()
+		require.NoError(t, subtree.SetLeaf(0, merkle.TreeNode{Value: uint256.NewInt(99)}))
+		require.Equal(t, root, tree.Root())
+		require.Equal(t, uint64(10), tree.Nodes[7].Value.Uint64())
+	})
+}
+
+func TestTree_CopySubtree_invalidIndex(t *testing.T) {
+	tree := makeTree(t)
+
+	for _, rootIndex := range []int{-1, 3, 6, 7} {
+		_, err := tree.CopySubtree(rootIndex)
+		require.Error(t, err, "root index %d", rootIndex)
+	}
+}
+
+func TestTreeDepthForLeaves(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		expected int
+		wantErr  bool
+	}{
+		{name: "zero", n: 0, expected: 1},
+		{name: "one", n: 1, expected: 1},
+		{name: "two", n: 2, expected: 1},
+		{name: "three", n: 3, expected: 2},
+		{name: "power of two", n: 1024, expected: 10},
+		{name: "above power of two", n: 1025, expected: 11},
+		{name: "maximum", n: 1 << merkle.TreeDepth, expected: merkle.TreeDepth},
+		{name: "above maximum", n: 1<<merkle.TreeDepth + 1, wantErr: true},
+		{name: "negative", n: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			depth, err := merkle.TreeDepthForLeaves(tt.n)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, depth)
+		})
+	}
+}
+
+func TestTree_Size(t *testing.T) {
+	tests := []struct {
+		name     string
+		depth    int
+		wantSize int
+	}{
+		{name: "depth 0", depth: 0, wantSize: 1},
+		{name: "depth 1", depth: 1, wantSize: 3},
+		{name: "depth 3", depth: 3, wantSize: 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, err := merkle.NewEmptyTree(tt.depth, merkle.EmptyLeafValue)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.wantSize, tree.Size())
+			require.Equal(t, 2*tree.GetLeavesAmount()-1, tree.Size())
+		})
+	}
+}
+
+func TestTree_Stats(t *testing.T) {
+	tree, err := merkle.NewEmptyTree(3, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+
+	require.NoError(t, tree.SetLeaf(0, merkle.TreeNode{Value: uint256.NewInt(10)}))
+	require.NoError(t, tree.SetLeaf(5, merkle.TreeNode{Value: uint256.NewInt(20)}))
+
+	require.Equal(t, merkle.TreeStats{
+		Depth:          3,
+		TotalLeaves:    8,
+		OccupiedLeaves: 2,
+		FillRatio:      0.25,
+	}, tree.Stats())
+}
+
+func TestTree_WithHashFunc(t *testing.T) {
+	sum := func(input []*big.Int) (*big.Int, error) {
+		res := new(big.Int)
+		for _, v := range input {
+			res.Add(res, v)
+		}
+
+		return res, nil
+	}
+
+	tree, err := merkle.NewEmptyTree(2, uint256.NewInt(1), merkle.WithHashFunc(sum))
+	require.NoError(t, err)
+	require.True(t, uint256.NewInt(4).Eq(tree.Root().Value))
+
+	require.NoError(t, tree.SetLeaf(2, merkle.TreeNode{Value: uint256.NewInt(10)}))
+	require.True(t, uint256.NewInt(13).Eq(tree.Root().Value))
+	require.True(t, uint256.NewInt(11).Eq(tree.Nodes[2].Value))
+}
+
+func TestNewTreeFromConfig(t *testing.T) {
+	expected := makeTree(t)
+
+	tree, err := merkle.NewTreeFromConfig(merkle.TreeConfig{Depth: 2})
+	require.NoError(t, err)
+	require.NoError(t, tree.SetLeaf(0, merkle.TreeNode{Value: uint256.NewInt(10)}))
+	require.NoError(t, tree.SetLeaf(1, merkle.TreeNode{Value: uint256.NewInt(20)}))
+	require.NoError(t, tree.SetLeaf(2, merkle.TreeNode{Value: uint256.NewInt(30)}))
+	require.NoError(t, tree.SetLeaf(3, merkle.TreeNode{Value: uint256.NewInt(40)}))
+
+	require.True(t, areTreeNodeSlicesEqual(expected.Nodes, tree.Nodes))
+
+	_, err = merkle.NewTreeFromConfig(merkle.TreeConfig{Depth: -1})
+	require.Error(t, err)
+}
+
+func TestNewEmptyTreeWithDepth(t *testing.T) {
+	tree, err := merkle.NewEmptyTreeWithDepth(3)
+	require.NoError(t, err)
+	require.True(t, merkle.EmptySubtreeHashes[3].Value.Eq(tree.Root().Value))
+}
+
 func areTreeNodeSlicesEqual(a, b []merkle.TreeNode) bool {
 	if len(a) != len(b) {
 		return false
@@ -140,3 +540,245 @@ func areTreeNodeSlicesEqual(a, b []merkle.TreeNode) bool {
 
 	return true
 }
+
+func TestGetSiblingPath(t *testing.T) {
+	tests := []struct {
+		name      string
+		leafIndex int
+		depth     int
+		expected  []int
+	}{
+		{name: "first leaf", leafIndex: 0, depth: 2, expected: []int{4, 2}},
+		{name: "second leaf", leafIndex: 1, depth: 2, expected: []int{3, 2}},
+		{name: "last leaf", leafIndex: 3, depth: 2, expected: []int{5, 1}},
+		{name: "root only", leafIndex: 0, depth: 0, expected: []int{}},
+		{name: "index out of range", leafIndex: 4, depth: 2, expected: nil},
+		{name: "negative index", leafIndex: -1, depth: 2, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, merkle.GetSiblingPath(tt.leafIndex, tt.depth))
+		})
+	}
+}
+
+func TestTree_GetPathToLeaf(t *testing.T) {
+	tree := makeTree(t)
+
+	tests := []struct {
+		name      string
+		leafIndex int
+		expected  []int
+		wantErr   bool
+	}{
+		{name: "first leaf", leafIndex: 0, expected: []int{0, 1, 3}},
+		{name: "second leaf", leafIndex: 1, expected: []int{0, 1, 4}},
+		{name: "last leaf", leafIndex: 3, expected: []int{0, 2, 6}},
+		{name: "index out of range", leafIndex: 4, wantErr: true},
+		{name: "negative index", leafIndex: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := tree.GetPathToLeaf(tt.leafIndex)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, path)
+		})
+	}
+}
+
+func TestTree_GetPathToLeaf_rootOnly(t *testing.T) {
+	tree, err := merkle.NewEmptyTree(0, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+
+	path, err := tree.GetPathToLeaf(0)
+	require.NoError(t, err)
+	require.Equal(t, []int{0}, path)
+}
+
+func TestGetSiblingPath_matchesProof(t *testing.T) {
+	tree := makeTree(t)
+
+	for i := 0; i < tree.GetLeavesAmount(); i++ {
+		proof, err := tree.GetProof(i)
+		require.NoError(t, err)
+
+		siblingPath := merkle.GetSiblingPath(i, 2)
+		require.Len(t, siblingPath, len(proof.Path))
+
+		for level, j := range siblingPath {
+			require.Equal(t, tree.Nodes[j], proof.Path[level])
+		}
+	}
+}
+
+func TestTree_StateHash(t *testing.T) {
+	tree := makeTree(t)
+
+	stateHash, err := tree.StateHash()
+	require.NoError(t, err)
+
+	sameStateHash, err := makeTree(t).StateHash()
+	require.NoError(t, err)
+	require.Equal(t, stateHash, sameStateHash)
+
+	require.NoError(t, tree.SetLeaf(1, merkle.TreeNode{Value: uint256.NewInt(21)}))
+
+	changedStateHash, err := tree.StateHash()
+	require.NoError(t, err)
+	require.NotEqual(t, stateHash, changedStateHash)
+
+	emptyTree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+
+	deeperEmptyTree, err := merkle.NewEmptyTree(3, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+
+	emptyStateHash, err := emptyTree.StateHash()
+	require.NoError(t, err)
+
+	deeperEmptyStateHash, err := deeperEmptyTree.StateHash()
+	require.NoError(t, err)
+	require.NotEqual(t, emptyStateHash, deeperEmptyStateHash)
+}
+
+func TestTree_StateHash_hashFunc(t *testing.T) {
+	sum := func(input []*big.Int) (*big.Int, error) {
+		res := new(big.Int)
+		for _, v := range input {
+			res.Add(res, v)
+		}
+
+		return res, nil
+	}
+
+	tree, err := merkle.NewEmptyTree(2, uint256.NewInt(1), merkle.WithHashFunc(sum))
+	require.NoError(t, err)
+
+	stateHash, err := tree.StateHash()
+	require.NoError(t, err)
+
+	stats := tree.Stats()
+	expected := new(uint256.Int).AddUint64(tree.Root().Value, uint64(stats.OccupiedLeaves+stats.Depth))
+	require.Equal(t, expected, stateHash.Value)
+}
+
+func TestTree_Prune(t *testing.T) {
+	for _, opts := range [][]merkle.TreeOption{nil, {merkle.WithDeferredHashing()}} {
+		tree, err := merkle.NewEmptyTree(3, merkle.EmptyLeafValue, opts...)
+		require.NoError(t, err)
+
+		expected, err := merkle.NewEmptyTree(3, merkle.EmptyLeafValue)
+		require.NoError(t, err)
+
+		for i := 0; i < 8; i++ {
+			require.NoError(t, tree.SetLeaf(i, merkle.TreeNode{Value: uint256.NewInt(uint64(10 + i))}))
+
+			if i >= 4 {
+				require.NoError(t, expected.SetLeaf(i, merkle.TreeNode{Value: uint256.NewInt(uint64(10 + i))}))
+			}
+		}
+
+		require.NoError(t, tree.Prune(4))
+		require.Equal(t, 4, tree.FirstOccupiedIndex)
+		require.Equal(t, expected.Root(), tree.Root())
+		require.NoError(t, tree.VerifyConsistency())
+		require.Equal(t, []int{4, 5, 6, 7}, tree.OccupiedLeafIndices())
+
+		require.NoError(t, tree.Prune(2))
+		require.Equal(t, 4, tree.FirstOccupiedIndex)
+	}
+}
+
+func TestTree_Prune_invalidBoundary(t *testing.T) {
+	tree := makeTree(t)
+
+	for _, keepFromLeafIndex := range []int{0, 3, 8, -1} {
+		require.Error(t, tree.Prune(keepFromLeafIndex), keepFromLeafIndex)
+	}
+
+	require.Zero(t, tree.FirstOccupiedIndex)
+}
+
+func TestNewTreeFromRootAndDepth(t *testing.T) {
+	tree := makeTree(t)
+
+	stub, err := merkle.NewTreeFromRootAndDepth(tree.Root(), 2)
+	require.NoError(t, err)
+	require.Equal(t, tree.Root(), stub.Root())
+
+	for i := 0; i < tree.GetLeavesAmount(); i++ {
+		proof, err := tree.GetProof(i)
+		require.NoError(t, err)
+		require.NoError(t, stub.VerifyProof(proof))
+
+		proof.Leaf = merkle.TreeNode{Value: uint256.NewInt(1)}
+		require.Error(t, stub.VerifyProof(proof))
+	}
+
+	_, err = merkle.NewTreeFromRootAndDepth(tree.Root(), -1)
+	require.Error(t, err)
+
+	_, err = merkle.NewTreeFromRootAndDepth(tree.Root(), merkle.TreeDepth+1)
+	require.Error(t, err)
+
+	_, err = merkle.NewTreeFromRootAndDepth(merkle.TreeNode{}, 2)
+	require.Error(t, err)
+
+	_, err = stub.GetProof(0)
+	require.Error(t, err)
+	require.Error(t, stub.SetLeaf(0, merkle.TreeNode{Value: uint256.NewInt(1)}))
+	require.Empty(t, stub.OccupiedLeafIndices())
+}
+
+func TestNewTreeFromRootAndDepth_ProductionDepth(t *testing.T) {
+	proof := merkle.Proof{
+		Leaf:      merkle.TreeNode{Value: uint256.NewInt(10)},
+		LeafIndex: 5,
+	}
+
+	node := proof.Leaf
+	for level := 0; level < merkle.TreeDepth; level++ {
+		sibling := merkle.TreeNode{Value: uint256.NewInt(uint64(level + 100))}
+		proof.Path = append(proof.Path, sibling)
+
+		left, right := node, sibling
+		if proof.LeafIndex>>level&1 == 1 {
+			left, right = sibling, node
+		}
+
+		val, err := merkle.HashFunc([]*big.Int{left.Value.ToBig(), right.Value.ToBig()})
+		require.NoError(t, err)
+		node = merkle.TreeNode{Value: uint256.MustFromBig(val)}
+	}
+
+	stub, err := merkle.NewTreeFromRootAndDepth(node, merkle.TreeDepth)
+	require.NoError(t, err)
+	require.Equal(t, 1<<merkle.TreeDepth, stub.GetLeavesAmount())
+	require.NoError(t, stub.VerifyProof(proof))
+
+	proof.LeafIndex = 4
+	require.Error(t, stub.VerifyProof(proof))
+}
+
+func TestTree_VerifyProof(t *testing.T) {
+	tree := makeTree(t)
+
+	proof, err := tree.GetProof(2)
+	require.NoError(t, err)
+	require.NoError(t, tree.VerifyProof(proof))
+
+	wrongIndex := proof
+	wrongIndex.LeafIndex = 3
+	require.Error(t, tree.VerifyProof(wrongIndex))
+
+	shortProof := proof
+	shortProof.Path = proof.Path[:1]
+	require.Error(t, tree.VerifyProof(shortProof))
+}
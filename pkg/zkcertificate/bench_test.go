@@ -0,0 +1,84 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/iden3/go-iden3-crypto/poseidon"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func BenchmarkPoseidon(b *testing.B) {
+	for _, n := range []int{2, 4, 6, 8, 12, 16} {
+		inputs := make([]*big.Int, n)
+		for i := range inputs {
+			inputs[i] = big.NewInt(int64(i + 1))
+		}
+
+		b.Run(fmt.Sprintf("N_%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := poseidon.Hash(inputs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkLeafHash(b *testing.B) {
+	privateKey := babyjub.NewRandPrivKey()
+	contentHash := zkcertificate.HashFromBigInt(big.NewInt(123456789))
+	holderCommitment := zkcertificate.HashFromBigInt(big.NewInt(7))
+	expirationDate := time.Unix(1900000000, 0)
+
+	signature, err := zkcertificate.SignCertificate(privateKey, contentHash, holderCommitment)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := zkcertificate.LeafHash(contentHash, privateKey.Public(), signature, holderCommitment, 1, expirationDate)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSignCertificate(b *testing.B) {
+	privateKey := babyjub.NewRandPrivKey()
+	contentHash := zkcertificate.HashFromBigInt(big.NewInt(123456789))
+	holderCommitment := zkcertificate.HashFromBigInt(big.NewInt(7))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := zkcertificate.SignCertificate(privateKey, contentHash, holderCommitment); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
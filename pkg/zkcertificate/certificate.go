@@ -16,14 +16,20 @@
 package zkcertificate
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
 	"github.com/iden3/go-iden3-crypto/babyjub"
 	"github.com/iden3/go-iden3-crypto/poseidon"
+	"github.com/iden3/go-iden3-crypto/utils"
 
 	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
 )
@@ -41,6 +47,17 @@ type Certificate[T any] struct {
 	ExpirationDate   Timestamp    `json:"expirationDate"`
 	Provider         ProviderData `json:"providerData"`
 	RandomSalt       int64        `json:"randomSalt"`
+	// LinkedPreviousDID is the DID of the previous certificate in a certificate chain, e.g. before renewal.
+	// If set, its hash is included into the leaf hash.
+	LinkedPreviousDID string `json:"linkedPreviousDid,omitempty"`
+	// StandardVersion is the version suffix of the Standard, see ParseStandardVersion. It is 0 for unversioned standards.
+	StandardVersion int `json:"zkCertStandardVersion,omitempty"`
+	// FieldsCommitment commits to the content fields for selective disclosure, see WithFieldsCommitment.
+	// If set, its root is included into the leaf hash.
+	FieldsCommitment *FieldsCommitment `json:"fieldsCommitment,omitempty"`
+	// SaltedContent makes the leaf hash use the salted content hash instead of the content hash,
+	// see Certificate.WithSaltedContent.
+	SaltedContent bool `json:"saltedContent,omitempty"`
 }
 
 // ProviderData represents the public key and signature data of a certificate provider.
@@ -58,9 +75,38 @@ type Content interface {
 	Standard() Standard
 }
 
+// ContentValidator checks domain-specific rules of certificate content,
+// e.g. that a date of birth is in the past or that a country code is valid.
+type ContentValidator[T any] interface {
+	Validate(content T) error
+}
+
+// ContentValidatorFunc is an adapter to use an ordinary function as a ContentValidator.
+type ContentValidatorFunc[T any] func(content T) error
+
+// Validate calls f(content).
+func (f ContentValidatorFunc[T]) Validate(content T) error {
+	return f(content)
+}
+
+// NewOption configures the creation of a certificate in New.
+type NewOption[T Content] func(o *newOptions[T])
+
+type newOptions[T Content] struct {
+	contentValidators []ContentValidator[T]
+}
+
+// WithContentValidator makes New reject content for which the validator returns an error.
+// The option can be given multiple times, validators are run in the given order.
+func WithContentValidator[T Content](v ContentValidator[T]) NewOption[T] {
+	return func(o *newOptions[T]) {
+		o.contentValidators = append(o.contentValidators, v)
+	}
+}
+
 // New creates a new certificate instance with the provided parameters and content.
 // It computes the content hash, verifies if the content was actually signed with providers public key,
-// and generates a leaf hash.
+// and generates a leaf hash. Content validators given with WithContentValidator are run before hashing.
 func New[T Content](
 	holderCommitment Hash,
 	content T,
@@ -68,23 +114,39 @@ func New[T Content](
 	providerSignature *babyjub.Signature,
 	salt int64,
 	expirationDate time.Time,
+	opts ...NewOption[T],
 ) (*Certificate[T], error) {
+	var options newOptions[T]
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if err := ValidateHolderCommitment(holderCommitment); err != nil {
+		return nil, wrapCertificateError(InvalidHolderCommitment, err, "invalid holder commitment")
+	}
+
+	for _, validator := range options.contentValidators {
+		if err := validator.Validate(content); err != nil {
+			return nil, wrapCertificateError(InvalidContent, err, "validate certificate content")
+		}
+	}
+
 	contentHash, err := content.Hash()
 	if err != nil {
-		return nil, fmt.Errorf("hash certificate content: %w", err)
+		return nil, wrapCertificateError(InvalidContentHash, err, "hash certificate content")
 	}
 
 	signatureValid, err := VerifySignature(providerPublicKey, contentHash, holderCommitment, providerSignature)
 	if err != nil {
-		return nil, fmt.Errorf("verify signature: %w", err)
+		return nil, wrapCertificateError(InvalidSignature, err, "verify signature")
 	}
 	if !signatureValid {
-		return nil, fmt.Errorf("invalid signature")
+		return nil, newCertificateError(InvalidSignature, "invalid signature")
 	}
 
 	leafHash, err := LeafHash(contentHash, providerPublicKey, providerSignature, holderCommitment, salt, expirationDate)
 	if err != nil {
-		return nil, fmt.Errorf("compute leaf hash: %w", err)
+		return nil, wrapCertificateError(HashingFailed, err, "compute leaf hash")
 	}
 
 	standard := content.Standard()
@@ -94,6 +156,7 @@ func New[T Content](
 		LeafHash:         leafHash,
 		DID:              DID(standard, leafHash),
 		Standard:         standard,
+		StandardVersion:  standardVersion(standard),
 		Content:          content,
 		ContentHash:      contentHash,
 		ExpirationDate:   Timestamp(expirationDate),
@@ -105,6 +168,377 @@ func New[T Content](
 	}, nil
 }
 
+// newSignedWithRandomSalt signs the content hash for the holder with the provider key
+// and creates a certificate with a random salt.
+func newSignedWithRandomSalt[T Content](
+	providerKey babyjub.PrivateKey,
+	holderCommitment Hash,
+	content T,
+	contentHash Hash,
+	expirationDate time.Time,
+) (*Certificate[T], error) {
+	signature, err := SignCertificate(providerKey, contentHash, holderCommitment)
+	if err != nil {
+		return nil, fmt.Errorf("sign certificate: %w", err)
+	}
+
+	salt, err := NewSaltFromRandom()
+	if err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+
+	return New(holderCommitment, content, providerKey.Public(), signature, salt, expirationDate)
+}
+
+// String implements [fmt.Stringer] and returns a single-line human-readable summary of the certificate.
+func (c Certificate[T]) String() string {
+	expirationDate := time.Time(c.ExpirationDate)
+
+	return fmt.Sprintf(
+		"Certificate{DID: %q, Standard: %q, Expiration: %q, Expired: %t}",
+		c.DID,
+		c.Standard,
+		expirationDate.UTC().Format(time.RFC3339),
+		time.Now().After(expirationDate),
+	)
+}
+
+// ExpirationWarning reports whether the certificate is not yet expired at the given time,
+// but expires within warnBefore from it.
+func (c *Certificate[T]) ExpirationWarning(now time.Time, warnBefore time.Duration) bool {
+	expirationDate := time.Time(c.ExpirationDate)
+
+	return !now.After(expirationDate) && !now.Add(warnBefore).Before(expirationDate)
+}
+
+// AgeAtExpiry computes the holder age in full years at the certificate expiration date.
+// Both dates are compared in UTC. Holders born on February 29 turn a year older on March 1 in non-leap years.
+func (c *Certificate[T]) AgeAtExpiry(dateOfBirth time.Time) (int, error) {
+	expirationDate := time.Time(c.ExpirationDate).UTC()
+	if expirationDate.IsZero() {
+		return 0, newCertificateError(InvalidArgument, "certificate has no expiration date")
+	}
+
+	dateOfBirth = dateOfBirth.UTC()
+	if dateOfBirth.After(expirationDate) {
+		return 0, newCertificateError(InvalidArgument, "date of birth is after the expiration date")
+	}
+
+	age := expirationDate.Year() - dateOfBirth.Year()
+	if expirationDate.Month() < dateOfBirth.Month() ||
+		expirationDate.Month() == dateOfBirth.Month() && expirationDate.Day() < dateOfBirth.Day() {
+		age--
+	}
+
+	return age, nil
+}
+
+// Clone returns a deep copy of the certificate, which can be modified independently of the original.
+// The content is deep copied if it provides a Clone() T method, otherwise it is copied by value.
+func (c *Certificate[T]) Clone() *Certificate[T] {
+	content := cloneContent(c.Content)
+
+	return &Certificate[T]{
+		HolderCommitment:  cloneHash(c.HolderCommitment),
+		LeafHash:          cloneHash(c.LeafHash),
+		DID:               c.DID,
+		Standard:          c.Standard,
+		StandardVersion:   c.StandardVersion,
+		Content:           content,
+		ContentHash:       cloneHash(c.ContentHash),
+		ExpirationDate:    c.ExpirationDate,
+		Provider:          c.Provider.clone(),
+		RandomSalt:        c.RandomSalt,
+		LinkedPreviousDID: c.LinkedPreviousDID,
+		FieldsCommitment:  c.FieldsCommitment.clone(),
+		SaltedContent:     c.SaltedContent,
+	}
+}
+
+// cloneContent deep copies the content if it provides a Clone() T method, otherwise it returns the content as is.
+func cloneContent[T any](content T) T {
+	if cloner, ok := any(content).(interface{ Clone() T }); ok {
+		return cloner.Clone()
+	}
+
+	return content
+}
+
+// ContentJSON returns the JSON encoding of the certificate content without the outer certificate envelope.
+func (c *Certificate[T]) ContentJSON() (json.RawMessage, error) {
+	data, err := json.Marshal(c.Content)
+	if err != nil {
+		return nil, wrapCertificateError(EncodingFailed, err, "encode certificate content")
+	}
+
+	return data, nil
+}
+
+// WithContent returns a new certificate with replaced content, re-signed with the provider key.
+// All other certificate fields, except for the random salt, are preserved.
+// The provider key must correspond to the public key stored in the certificate.
+func (c *Certificate[T]) WithContent(newContent T, providerKey babyjub.PrivateKey, salt int64) (*Certificate[T], error) {
+	content, ok := any(newContent).(Content)
+	if !ok {
+		return nil, newCertificateError(InvalidArgument, "certificate content of type %T does not implement Content", newContent)
+	}
+
+	if standard := content.Standard(); standard != c.Standard {
+		return nil, newCertificateError(
+			InvalidArgument,
+			"content standard %q does not match certificate standard %q",
+			standard,
+			c.Standard,
+		)
+	}
+
+	providerPublicKey := providerKey.Public()
+	if providerPublicKey.X.Cmp(c.Provider.PublicKey.X) != 0 || providerPublicKey.Y.Cmp(c.Provider.PublicKey.Y) != 0 {
+		return nil, newCertificateError(InvalidProviderData, "provider key does not match certificate provider")
+	}
+
+	contentHash, err := content.Hash()
+	if err != nil {
+		return nil, wrapCertificateError(InvalidContentHash, err, "hash certificate content")
+	}
+
+	if contentHash.Cmp(c.ContentHash) == 0 {
+		return nil, newCertificateError(InvalidContentHash, "new content has the same hash as the current one")
+	}
+
+	signature, err := SignCertificate(providerKey, contentHash, c.HolderCommitment)
+	if err != nil {
+		return nil, wrapCertificateError(InvalidSignature, err, "sign certificate")
+	}
+
+	certificate := &Certificate[T]{
+		HolderCommitment: c.HolderCommitment,
+		Standard:         c.Standard,
+		StandardVersion:  c.StandardVersion,
+		Content:          newContent,
+		ContentHash:      contentHash,
+		ExpirationDate:   c.ExpirationDate,
+		Provider: ProviderData{
+			PublicKey: *providerPublicKey,
+			Signature: *signature,
+		},
+		RandomSalt:        salt,
+		LinkedPreviousDID: c.LinkedPreviousDID,
+		SaltedContent:     c.SaltedContent,
+	}
+
+	if c.FieldsCommitment != nil {
+		if certificate.FieldsCommitment, err = newFieldsCommitment(certificate); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := certificate.updateLeafHash(); err != nil {
+		return nil, err
+	}
+
+	return certificate, nil
+}
+
+// LinkToPrevious returns a copy of the certificate linked to the previous certificate in a chain.
+// The hash of the previous certificate DID is included into the leaf hash, so the link is verifiable in ZK.
+func (c *Certificate[T]) LinkToPrevious(previousDID string) (*Certificate[T], error) {
+	if !strings.HasPrefix(previousDID, "did:") {
+		return nil, newCertificateError(InvalidArgument, "invalid previous did %q", previousDID)
+	}
+
+	if previousDID == c.DID {
+		return nil, newCertificateError(InvalidChain, "certificate can not be linked to itself")
+	}
+
+	certificate := *c
+	certificate.LinkedPreviousDID = previousDID
+
+	if err := certificate.updateLeafHash(); err != nil {
+		return nil, err
+	}
+
+	return &certificate, nil
+}
+
+// WithSalt returns a copy of the certificate with the given random salt and the recomputed leaf hash and DID.
+// The signature does not depend on the salt, so the certificate is not re-signed.
+func (c *Certificate[T]) WithSalt(newSalt int64) (*Certificate[T], error) {
+	if newSalt == c.RandomSalt {
+		return nil, newCertificateError(InvalidArgument, "new salt equals the current salt")
+	}
+
+	certificate := *c
+	certificate.RandomSalt = newSalt
+
+	if err := certificate.updateLeafHash(); err != nil {
+		return nil, err
+	}
+
+	return &certificate, nil
+}
+
+// WithSaltedContent returns a copy of the certificate whose leaf hash uses the salted content hash
+// instead of the content hash, see SaltedContentHash. The mode is stored with the certificate,
+// so the leaf hash is recomputed the same way whenever the certificate is modified or verified.
+func (c *Certificate[T]) WithSaltedContent() (*Certificate[T], error) {
+	if c.SaltedContent {
+		return nil, newCertificateError(InvalidArgument, "certificate already uses salted content")
+	}
+
+	certificate := *c
+	certificate.SaltedContent = true
+
+	if err := certificate.updateLeafHash(); err != nil {
+		return nil, err
+	}
+
+	return &certificate, nil
+}
+
+// Fingerprint computes a stable identity hash of the certificate as
+// Poseidon(contentHash, holderCommitment, providerPublicKey.X, providerPublicKey.Y).
+// Unlike the leaf hash, it does not depend on the expiration date, salt or signature,
+// so it stays the same across re-issuances with the same content, holder, and provider.
+func (c *Certificate[T]) Fingerprint() (Hash, error) {
+	if c.Provider.PublicKey.X == nil || c.Provider.PublicKey.Y == nil {
+		return Hash{}, newCertificateError(InvalidProviderData, "incomplete provider public key")
+	}
+
+	hash, err := poseidon.Hash([]*big.Int{
+		c.ContentHash.BigInt(),
+		c.HolderCommitment.BigInt(),
+		c.Provider.PublicKey.X,
+		c.Provider.PublicKey.Y,
+	})
+	if err != nil {
+		return Hash{}, wrapCertificateError(HashingFailed, err, "compute hash")
+	}
+
+	return HashFromBigInt(hash), nil
+}
+
+// HashWithNonce computes Poseidon(leafHash, nonce) of the certificate.
+// With the holder secret as nonce, it is the standard nullifier of the certificate used in ZK proofs.
+// The nonce must be a field element.
+func (c *Certificate[T]) HashWithNonce(nonce *big.Int) (Hash, error) {
+	if nonce == nil || nonce.Sign() < 0 || !utils.CheckBigIntInField(nonce) {
+		return Hash{}, newCertificateError(InvalidArgument, "nonce is not in the field")
+	}
+
+	hash, err := poseidon.Hash([]*big.Int{c.LeafHash.BigInt(), nonce})
+	if err != nil {
+		return Hash{}, wrapCertificateError(HashingFailed, err, "compute hash")
+	}
+
+	return HashFromBigInt(hash), nil
+}
+
+// SaltedContentHash computes Poseidon(contentHash, randomSalt) of the certificate.
+// Unlike the content hash, it can not be reproduced by guessing content field values.
+func (c *Certificate[T]) SaltedContentHash() (Hash, error) {
+	return saltedContentHash(c.ContentHash, c.RandomSalt)
+}
+
+// ProviderPublicKeyHash computes Poseidon(Ax, Ay) of the provider public key.
+// It identifies the provider by a single field element, which is also computable in ZK circuits.
+func (c *Certificate[T]) ProviderPublicKeyHash() (Hash, error) {
+	if c.Provider.PublicKey.X == nil || c.Provider.PublicKey.Y == nil {
+		return Hash{}, newCertificateError(InvalidProviderData, "incomplete provider public key")
+	}
+
+	hash, err := poseidon.Hash([]*big.Int{c.Provider.PublicKey.X, c.Provider.PublicKey.Y})
+	if err != nil {
+		return Hash{}, wrapCertificateError(HashingFailed, err, "compute hash")
+	}
+
+	return HashFromBigInt(hash), nil
+}
+
+// computeLeafHash computes the leaf hash of the certificate from its current fields.
+func (c *Certificate[T]) computeLeafHash() (Hash, error) {
+	return LeafHash(
+		c.ContentHash,
+		&c.Provider.PublicKey,
+		&c.Provider.Signature,
+		c.HolderCommitment,
+		c.RandomSalt,
+		time.Time(c.ExpirationDate),
+		c.leafHashOptions()...,
+	)
+}
+
+// leafHashOptions returns the LeafHash options matching the optional leaf hash inputs of the certificate.
+func (c *Certificate[T]) leafHashOptions() []LeafHashOption {
+	var opts []LeafHashOption
+
+	if c.SaltedContent {
+		opts = append(opts, WithSaltedContent())
+	}
+
+	if c.LinkedPreviousDID != "" {
+		opts = append(opts, withPreviousDID(c.LinkedPreviousDID))
+	}
+
+	if c.FieldsCommitment != nil {
+		opts = append(opts, WithFieldsRoot(c.FieldsCommitment.Root))
+	}
+
+	return opts
+}
+
+// updateLeafHash recomputes the leaf hash and DID of the certificate.
+func (c *Certificate[T]) updateLeafHash() error {
+	leafHash, err := c.computeLeafHash()
+	if err != nil {
+		return wrapCertificateError(HashingFailed, err, "compute leaf hash")
+	}
+
+	c.LeafHash = leafHash
+	c.DID = DID(c.Standard, leafHash)
+
+	return nil
+}
+
+// VerifyChain verifies a chain of linked certificates ordered from the oldest to the newest one.
+// Every certificate except for the first one must be linked to the DID of its predecessor,
+// and the leaf hash and DID of every certificate must match its content.
+func VerifyChain[T any](chain []*Certificate[T]) error {
+	for i, certificate := range chain {
+		leafHash, err := certificate.computeLeafHash()
+		if err != nil {
+			return wrapCertificateError(HashingFailed, err, "compute leaf hash of certificate %d", i)
+		}
+
+		if leafHash.Cmp(certificate.LeafHash) != 0 {
+			return newCertificateError(InvalidLeafHash, "certificate %d has invalid leaf hash", i)
+		}
+
+		if certificate.DID != DID(certificate.Standard, certificate.LeafHash) {
+			return newCertificateError(InvalidLeafHash, "certificate %d has invalid did", i)
+		}
+
+		if i > 0 && certificate.LinkedPreviousDID != chain[i-1].DID {
+			return newCertificateError(InvalidChain, "certificate %d is not linked to certificate %d", i, i-1)
+		}
+	}
+
+	return nil
+}
+
+// validate checks that the provider public key and signature are complete.
+func (p *ProviderData) validate() error {
+	if p.PublicKey.X == nil || p.PublicKey.Y == nil {
+		return newCertificateError(InvalidProviderData, "incomplete provider public key")
+	}
+
+	if p.Signature.R8 == nil || p.Signature.R8.X == nil || p.Signature.R8.Y == nil || p.Signature.S == nil {
+		return newCertificateError(InvalidProviderData, "incomplete provider signature")
+	}
+
+	return nil
+}
+
 type providerDataDTO struct {
 	Ax  string `json:"ax"`
 	Bx  string `json:"bx"`
@@ -128,43 +562,190 @@ func (p ProviderData) MarshalJSON() ([]byte, error) {
 func (p *ProviderData) UnmarshalJSON(data []byte) error {
 	var dto providerDataDTO
 	if err := json.Unmarshal(data, &dto); err != nil {
-		return err
+		return wrapCertificateError(EncodingFailed, err, "decode provider data")
 	}
 
 	var ok bool
 
 	p.PublicKey.X, ok = new(big.Int).SetString(dto.Ax, 10)
 	if !ok {
-		return fmt.Errorf("invalid x coordinate of public key point")
+		return newCertificateError(InvalidProviderData, "invalid x coordinate of public key point")
 	}
 
 	p.PublicKey.Y, ok = new(big.Int).SetString(dto.Bx, 10)
 	if !ok {
-		return fmt.Errorf("invalid y coordinate of public key point")
+		return newCertificateError(InvalidProviderData, "invalid y coordinate of public key point")
 	}
 
 	signatureR8Point := &babyjub.Point{}
 
 	signatureR8Point.X, ok = new(big.Int).SetString(dto.R8x, 10)
 	if !ok {
-		return fmt.Errorf("invalid x coordinate of signature r8 point")
+		return newCertificateError(InvalidProviderData, "invalid x coordinate of signature r8 point")
 	}
 
 	signatureR8Point.Y, ok = new(big.Int).SetString(dto.R8y, 10)
 	if !ok {
-		return fmt.Errorf("invalid y coordinate of signature r8 point")
+		return newCertificateError(InvalidProviderData, "invalid y coordinate of signature r8 point")
 	}
 
 	p.Signature.R8 = signatureR8Point
 
 	p.Signature.S, ok = new(big.Int).SetString(dto.S, 10)
 	if !ok {
-		return fmt.Errorf("invalid s component of signature")
+		return newCertificateError(InvalidProviderData, "invalid s component of signature")
 	}
 
 	return nil
 }
 
+// Equal reports whether both provider data hold the same public key and signature
+// by comparing their field elements.
+func (p ProviderData) Equal(other ProviderData) bool {
+	return bigIntsEqual(p.PublicKey.X, other.PublicKey.X) &&
+		bigIntsEqual(p.PublicKey.Y, other.PublicKey.Y) &&
+		pointsEqual(p.Signature.R8, other.Signature.R8) &&
+		bigIntsEqual(p.Signature.S, other.Signature.S)
+}
+
+func pointsEqual(a, b *babyjub.Point) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return bigIntsEqual(a.X, b.X) && bigIntsEqual(a.Y, b.Y)
+}
+
+func (p ProviderData) clone() ProviderData {
+	res := ProviderData{
+		PublicKey: babyjub.PublicKey{
+			X: cloneBigInt(p.PublicKey.X),
+			Y: cloneBigInt(p.PublicKey.Y),
+		},
+		Signature: babyjub.Signature{
+			S: cloneBigInt(p.Signature.S),
+		},
+	}
+
+	if p.Signature.R8 != nil {
+		res.Signature.R8 = &babyjub.Point{
+			X: cloneBigInt(p.Signature.R8.X),
+			Y: cloneBigInt(p.Signature.R8.Y),
+		}
+	}
+
+	return res
+}
+
+func cloneBigInt(n *big.Int) *big.Int {
+	if n == nil {
+		return nil
+	}
+
+	return new(big.Int).Set(n)
+}
+
+func bigIntsEqual(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Cmp(b) == 0
+}
+
+// CompressedPublicKey returns the compressed encoding of the provider's public key.
+// The Y coordinate is packed in little-endian format with the highest bit set to the sign of X.
+func (p ProviderData) CompressedPublicKey() [32]byte {
+	return p.PublicKey.Compress()
+}
+
+// ToEthereumAddress derives the Ethereum address identifying the provider on-chain
+// as the last 20 bytes of keccak256 of the compressed public key.
+func (p ProviderData) ToEthereumAddress() (common.Address, error) {
+	if p.PublicKey.X == nil || p.PublicKey.Y == nil {
+		return common.Address{}, newCertificateError(InvalidProviderData, "incomplete provider public key")
+	}
+
+	compressed := p.CompressedPublicKey()
+
+	return common.BytesToAddress(crypto.Keccak256(compressed[:])[12:]), nil
+}
+
+// DecompressPublicKey restores a Baby Jubjub public key from its compressed encoding.
+func DecompressPublicKey(compressed [32]byte) (*babyjub.PublicKey, error) {
+	publicKeyComp := babyjub.PublicKeyComp(compressed)
+
+	publicKey, err := publicKeyComp.Decompress()
+	if err != nil {
+		return nil, wrapCertificateError(InvalidProviderData, err, "decompress public key point")
+	}
+
+	return publicKey, nil
+}
+
+// MarshalCompact encodes the provider data as a base64url string of a 160-byte blob consisting of
+// Ax, Ay, R8x, R8y and S, each encoded as a 32-byte little-endian integer.
+func (p ProviderData) MarshalCompact() (string, error) {
+	if p.PublicKey.X == nil || p.PublicKey.Y == nil {
+		return "", newCertificateError(InvalidProviderData, "incomplete public key")
+	}
+
+	if !utils.CheckBigIntInField(p.PublicKey.X) || !utils.CheckBigIntInField(p.PublicKey.Y) {
+		return "", newCertificateError(InvalidProviderData, "public key point is not in the field")
+	}
+
+	signature, err := SignatureToBytes(&p.Signature)
+	if err != nil {
+		return "", wrapCertificateError(InvalidProviderData, err, "encode signature")
+	}
+
+	ax := utils.BigIntLEBytes(p.PublicKey.X)
+	ay := utils.BigIntLEBytes(p.PublicKey.Y)
+
+	data := make([]byte, 0, 160)
+	data = append(data, ax[:]...)
+	data = append(data, ay[:]...)
+	data = append(data, signature[:]...)
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// UnmarshalCompact decodes the provider data encoded with MarshalCompact.
+// It validates that the public key and signature points are on the Baby Jubjub curve.
+func (p *ProviderData) UnmarshalCompact(s string) error {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return wrapCertificateError(EncodingFailed, err, "decode base64")
+	}
+
+	if len(data) != 160 {
+		return newCertificateError(EncodingFailed, "invalid data length %d, want 160", len(data))
+	}
+
+	publicKey := babyjub.PublicKey{
+		X: utils.SetBigIntFromLEBytes(new(big.Int), data[0:32]),
+		Y: utils.SetBigIntFromLEBytes(new(big.Int), data[32:64]),
+	}
+
+	if !utils.CheckBigIntInField(publicKey.X) || !utils.CheckBigIntInField(publicKey.Y) {
+		return newCertificateError(InvalidProviderData, "public key point is not in the field")
+	}
+
+	if !publicKey.Point().InCurve() {
+		return newCertificateError(InvalidProviderData, "public key point is not on the curve")
+	}
+
+	signature, err := SignatureFromBytes([96]byte(data[64:160]))
+	if err != nil {
+		return wrapCertificateError(InvalidProviderData, err, "decode signature")
+	}
+
+	p.PublicKey = publicKey
+	p.Signature = *signature
+
+	return nil
+}
+
 // IssuedCertificate represents a certificate that has been issued and includes registration details.
 type IssuedCertificate[T any] struct {
 	Certificate[T] `json:",inline"`
@@ -179,23 +760,206 @@ type RegistrationDetails struct {
 	LeafIndex int            `json:"leafIndex"`
 }
 
+// ToOnChainRepresentation converts the certificate into the arguments of registry contract functions:
+// the leaf hash as a big-endian bytes32, the registry address and the leaf index as uint256.
+func (c *IssuedCertificate[T]) ToOnChainRepresentation() (
+	leafHash [32]byte,
+	registryAddress common.Address,
+	leafIndex *big.Int,
+) {
+	return c.LeafHash.Bytes32(), c.Registration.Address, big.NewInt(int64(c.Registration.LeafIndex))
+}
+
+// ValidateRegistration checks the registration details and that the Merkle proof
+// corresponds to the registered leaf of the certificate.
+func (c *IssuedCertificate[T]) ValidateRegistration() error {
+	if err := c.Registration.Validate(); err != nil {
+		return wrapCertificateError(InvalidRegistration, err, "validate registration details")
+	}
+
+	if c.MerkleProof.LeafIndex != c.Registration.LeafIndex {
+		return newCertificateError(
+			InvalidMerkleProof,
+			"merkle proof leaf index %d does not match registration leaf index %d",
+			c.MerkleProof.LeafIndex,
+			c.Registration.LeafIndex,
+		)
+	}
+
+	if c.MerkleProof.Leaf.Value == nil || c.MerkleProof.Leaf.Value.ToBig().Cmp(c.LeafHash.BigInt()) != 0 {
+		return newCertificateError(InvalidMerkleProof, "merkle proof leaf does not match certificate leaf hash")
+	}
+
+	return nil
+}
+
+// UpdateMerkleProof replaces the stored Merkle proof with a fresh one from the given tree.
+// The tree must contain the certificate leaf hash at the registered leaf index.
+func (c *IssuedCertificate[T]) UpdateMerkleProof(tree *merkle.Tree) error {
+	leaf, err := tree.GetLeaf(c.Registration.LeafIndex)
+	if err != nil {
+		return wrapCertificateError(InvalidRegistration, err, "get leaf")
+	}
+
+	if leaf.Value.ToBig().Cmp(c.LeafHash.BigInt()) != 0 {
+		return newCertificateError(
+			InvalidRegistration,
+			"leaf at index %d does not match certificate leaf hash",
+			c.Registration.LeafIndex,
+		)
+	}
+
+	proof, err := tree.GetProof(c.Registration.LeafIndex)
+	if err != nil {
+		return wrapCertificateError(InvalidMerkleProof, err, "get proof")
+	}
+
+	if err := tree.VerifyProof(proof); err != nil {
+		return wrapCertificateError(InvalidMerkleProof, err, "verify proof")
+	}
+
+	c.MerkleProof = proof
+
+	return nil
+}
+
+// UpdateExpiration extends the certificate to the new expiration date.
+// The certificate is re-signed with the provider key, which must match the certificate provider,
+// and its new leaf hash replaces the old one at the registered leaf index of the tree,
+// which must hold the current leaf hash. The new leaf hash and Merkle proof are computed
+// before the tree is modified, and the certificate is only modified after the tree was updated.
+func (c *IssuedCertificate[T]) UpdateExpiration(
+	tree *merkle.Tree,
+	providerKey babyjub.PrivateKey,
+	newExpiration time.Time,
+) error {
+	if !newExpiration.After(time.Time(c.ExpirationDate)) {
+		return newCertificateError(InvalidArgument, "new expiration date must be after the current one")
+	}
+
+	providerPublicKey := providerKey.Public()
+	if providerPublicKey.X.Cmp(c.Provider.PublicKey.X) != 0 || providerPublicKey.Y.Cmp(c.Provider.PublicKey.Y) != 0 {
+		return newCertificateError(InvalidProviderData, "provider key does not match certificate provider")
+	}
+
+	oldLeaf, err := tree.GetLeaf(c.Registration.LeafIndex)
+	if err != nil {
+		return wrapCertificateError(InvalidRegistration, err, "get leaf")
+	}
+
+	if oldLeaf.Value.ToBig().Cmp(c.LeafHash.BigInt()) != 0 {
+		return newCertificateError(
+			InvalidRegistration,
+			"leaf at index %d does not match certificate leaf hash",
+			c.Registration.LeafIndex,
+		)
+	}
+
+	signature, err := SignCertificate(providerKey, c.ContentHash, c.HolderCommitment)
+	if err != nil {
+		return wrapCertificateError(InvalidSignature, err, "sign certificate")
+	}
+
+	updated := *c
+	updated.Provider = ProviderData{
+		PublicKey: *providerPublicKey,
+		Signature: *signature,
+	}
+	updated.ExpirationDate = Timestamp(newExpiration)
+
+	if err := updated.updateLeafHash(); err != nil {
+		return err
+	}
+
+	newLeaf := merkle.TreeNode{Value: uint256.MustFromBig(updated.LeafHash.BigInt())}
+
+	// The siblings of the leaf do not change, so the proof of the new leaf is known before the tree is modified.
+	proof, err := tree.GetProof(c.Registration.LeafIndex)
+	if err != nil {
+		return wrapCertificateError(InvalidMerkleProof, err, "get proof")
+	}
+	proof.Leaf = newLeaf
+
+	if err := tree.SetLeaf(c.Registration.LeafIndex, newLeaf); err != nil {
+		return wrapCertificateError(InvalidMerkleProof, err, "set leaf")
+	}
+
+	updated.MerkleProof = proof
+	*c = updated
+
+	return nil
+}
+
+// Validate checks that the registry address is a non-zero valid address and the leaf index is non-negative.
+func (r RegistrationDetails) Validate() error {
+	if r.Address == (common.Address{}) {
+		return newCertificateError(InvalidRegistration, "registry address is zero")
+	}
+
+	if !common.IsHexAddress(r.Address.Hex()) {
+		return newCertificateError(InvalidRegistration, "invalid registry address %s", r.Address.Hex())
+	}
+
+	if r.LeafIndex < 0 {
+		return newCertificateError(InvalidRegistration, "invalid leaf index %d", r.LeafIndex)
+	}
+
+	return nil
+}
+
 // SignCertificate generates a digital signature for a certificate using the provider's private key.
 func SignCertificate(
 	providerKey babyjub.PrivateKey,
 	contentHash Hash,
 	commitmentHash Hash,
 ) (*babyjub.Signature, error) {
+	return SignCertificateWithContext(context.Background(), providerKey, contentHash, commitmentHash)
+}
+
+// SignCertificateWithContext generates a digital signature for a certificate using the provider's private key.
+// The context is checked before every step, so the signing is aborted once the context is canceled.
+func SignCertificateWithContext(
+	ctx context.Context,
+	providerKey babyjub.PrivateKey,
+	contentHash Hash,
+	commitmentHash Hash,
+) (*babyjub.Signature, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, wrapCertificateError(Canceled, err, "sign certificate")
+	}
+
+	if err := validateMessageHashes(contentHash, commitmentHash); err != nil {
+		return nil, err
+	}
+
 	message, err := poseidon.Hash([]*big.Int{contentHash.BigInt(), commitmentHash.BigInt()})
 	if err != nil {
-		return nil, fmt.Errorf("hash message: %w", err)
+		return nil, wrapCertificateError(HashingFailed, err, "hash message")
 	}
 
 	// TODO: Why mod here? It doesn't crash without mod
 	// message = message.Mod(message, utils.NewIntFromString("2736030358979909402780800718157159386076813972158567259200215660948447373040"))
 
+	if err := ctx.Err(); err != nil {
+		return nil, wrapCertificateError(Canceled, err, "sign certificate")
+	}
+
 	return providerKey.SignPoseidon(message), nil
 }
 
+// validateMessageHashes checks that the content and commitment hashes are field elements.
+func validateMessageHashes(contentHash, commitmentHash Hash) error {
+	if err := ValidateHash(contentHash); err != nil {
+		return wrapCertificateError(InvalidArgument, err, "invalid content hash")
+	}
+
+	if err := ValidateHash(commitmentHash); err != nil {
+		return wrapCertificateError(InvalidArgument, err, "invalid commitment hash")
+	}
+
+	return nil
+}
+
 // VerifySignature verifies the digital signature of a certificate using the provider's public key.
 func VerifySignature(
 	providerKey *babyjub.PublicKey,
@@ -203,14 +967,49 @@ func VerifySignature(
 	commitmentHash Hash,
 	signature *babyjub.Signature,
 ) (bool, error) {
+	if err := validateMessageHashes(contentHash, commitmentHash); err != nil {
+		return false, err
+	}
+
 	message, err := poseidon.Hash([]*big.Int{contentHash.BigInt(), commitmentHash.BigInt()})
 	if err != nil {
-		return false, fmt.Errorf("hash message: %w", err)
+		return false, wrapCertificateError(HashingFailed, err, "hash message")
 	}
 
 	return providerKey.VerifyPoseidon(message, signature), nil
 }
 
+// LeafHashOption configures the leaf hash computation of LeafHash.
+type LeafHashOption func(o *leafHashOptions)
+
+type leafHashOptions struct {
+	saltedContent bool
+	previousDID   string
+	fieldsRoot    *Hash
+}
+
+// WithSaltedContent makes LeafHash use the salted content hash Poseidon(contentHash, salt)
+// instead of the plain content hash, which prevents content enumeration by guessing field values.
+func WithSaltedContent() LeafHashOption {
+	return func(o *leafHashOptions) {
+		o.saltedContent = true
+	}
+}
+
+// WithFieldsRoot makes LeafHash include the root of the content fields tree, see FieldsCommitment.
+func WithFieldsRoot(root Hash) LeafHashOption {
+	return func(o *leafHashOptions) {
+		o.fieldsRoot = &root
+	}
+}
+
+// withPreviousDID makes LeafHash include the hash of the previous certificate DID, see LinkedLeafHash.
+func withPreviousDID(previousDID string) LeafHashOption {
+	return func(o *leafHashOptions) {
+		o.previousDID = previousDID
+	}
+}
+
 // LeafHash computes the hash of a certificate's components and additional data to create a leaf hash.
 func LeafHash(
 	contentHash Hash,
@@ -219,8 +1018,92 @@ func LeafHash(
 	commitmentHash Hash,
 	salt int64,
 	expirationDate time.Time,
+	opts ...LeafHashOption,
 ) (Hash, error) {
-	hash, err := poseidon.Hash([]*big.Int{
+	var options leafHashOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if err := validateMessageHashes(contentHash, commitmentHash); err != nil {
+		return Hash{}, err
+	}
+
+	if options.saltedContent {
+		var err error
+		contentHash, err = saltedContentHash(contentHash, salt)
+		if err != nil {
+			return Hash{}, wrapCertificateError(HashingFailed, err, "compute salted content hash")
+		}
+	}
+
+	var extraInputs []*big.Int
+
+	if options.previousDID != "" {
+		previousDIDHash, err := poseidon.HashBytes([]byte(options.previousDID))
+		if err != nil {
+			return Hash{}, wrapCertificateError(HashingFailed, err, "hash previous did")
+		}
+
+		extraInputs = append(extraInputs, previousDIDHash)
+	}
+
+	if options.fieldsRoot != nil {
+		if err := ValidateHash(*options.fieldsRoot); err != nil {
+			return Hash{}, wrapCertificateError(InvalidArgument, err, "invalid fields root")
+		}
+
+		extraInputs = append(extraInputs, options.fieldsRoot.BigInt())
+	}
+
+	return leafHash(contentHash, providerPublicKey, signature, commitmentHash, salt, expirationDate, extraInputs...)
+}
+
+func saltedContentHash(contentHash Hash, salt int64) (Hash, error) {
+	hash, err := poseidon.Hash([]*big.Int{contentHash.BigInt(), big.NewInt(salt)})
+	if err != nil {
+		return Hash{}, wrapCertificateError(HashingFailed, err, "compute hash")
+	}
+
+	return HashFromBigInt(hash), nil
+}
+
+// LinkedLeafHash computes the leaf hash of a certificate linked to a previous certificate.
+// It extends the LeafHash inputs with the Poseidon hash of the previous certificate DID.
+func LinkedLeafHash(
+	contentHash Hash,
+	providerPublicKey *babyjub.PublicKey,
+	signature *babyjub.Signature,
+	commitmentHash Hash,
+	salt int64,
+	expirationDate time.Time,
+	previousDID string,
+) (Hash, error) {
+	if previousDID == "" {
+		return Hash{}, newCertificateError(InvalidArgument, "previous did is empty")
+	}
+
+	return LeafHash(
+		contentHash,
+		providerPublicKey,
+		signature,
+		commitmentHash,
+		salt,
+		expirationDate,
+		withPreviousDID(previousDID),
+	)
+}
+
+func leafHash(
+	contentHash Hash,
+	providerPublicKey *babyjub.PublicKey,
+	signature *babyjub.Signature,
+	commitmentHash Hash,
+	salt int64,
+	expirationDate time.Time,
+	extraInputs ...*big.Int,
+) (Hash, error) {
+	inputs := append([]*big.Int{
 		contentHash.BigInt(),
 		providerPublicKey.X,
 		providerPublicKey.Y,
@@ -230,9 +1113,11 @@ func LeafHash(
 		commitmentHash.BigInt(),
 		big.NewInt(salt),
 		big.NewInt(expirationDate.Unix()),
-	})
+	}, extraInputs...)
+
+	hash, err := poseidon.Hash(inputs)
 	if err != nil {
-		return Hash{}, fmt.Errorf("compute hash: %w", err)
+		return Hash{}, wrapCertificateError(HashingFailed, err, "compute hash")
 	}
 
 	return HashFromBigInt(hash), nil
@@ -0,0 +1,265 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/iden3/go-iden3-crypto/babyjub"
+)
+
+// ErrAuditChainBroken is returned when the hash chain of an audit log does not match its entries.
+var ErrAuditChainBroken = errors.New("audit log hash chain is broken")
+
+// AuditOperation is a certificate operation recorded in an audit log.
+type AuditOperation string
+
+// Operations recorded by CertificateAuditor.
+const (
+	AuditOperationCreate  AuditOperation = "create"
+	AuditOperationReissue AuditOperation = "reissue"
+	AuditOperationRevoke  AuditOperation = "revoke"
+)
+
+// AuditEntry is a single record of an audit log.
+// Hash covers all other fields including the hash of the previous entry,
+// so modifying, removing or reordering entries breaks the chain.
+type AuditEntry struct {
+	Timestamp         time.Time      `json:"timestamp"`
+	Operation         AuditOperation `json:"operation"`
+	CertificateDID    string         `json:"certificateDid"`
+	OperatorPublicKey string         `json:"operatorPublicKey"`
+	PreviousHash      string         `json:"previousHash"`
+	Hash              string         `json:"hash"`
+}
+
+// computeHash returns the hex encoded SHA-256 hash of the entry with the Hash field left out.
+func (e AuditEntry) computeHash() (string, error) {
+	e.Hash = ""
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", fmt.Errorf("encode audit entry: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// AuditLog is an append-only log of certificate operations.
+// Every appended entry is written to the underlying writer as a line of JSON.
+type AuditLog struct {
+	mu      sync.Mutex
+	w       io.Writer
+	entries []AuditEntry
+}
+
+// NewAuditLog creates an empty audit log writing its entries to w.
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w}
+}
+
+// ReadAuditLog reads the entries of an audit log previously written by AuditLog from r.
+// Entries appended afterwards are written to w, which may be the same file opened in append mode.
+// The entries are not checked, call Verify to detect tampering.
+func ReadAuditLog(r io.Reader, w io.Writer) (*AuditLog, error) {
+	log := NewAuditLog(w)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("decode audit entry on line %d: %w", line, err)
+		}
+
+		log.entries = append(log.entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read audit log: %w", err)
+	}
+
+	return log, nil
+}
+
+// Append records an operation on the certificate with the given DID performed by the operator.
+func (l *AuditLog) Append(
+	operation AuditOperation,
+	certificateDID string,
+	operator *babyjub.PublicKey,
+	timestamp time.Time,
+) (AuditEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	operatorKey := operator.Compress()
+	entry := AuditEntry{
+		Timestamp:         timestamp.UTC().Round(0),
+		Operation:         operation,
+		CertificateDID:    certificateDID,
+		OperatorPublicKey: hex.EncodeToString(operatorKey[:]),
+	}
+	if len(l.entries) > 0 {
+		entry.PreviousHash = l.entries[len(l.entries)-1].Hash
+	}
+
+	hash, err := entry.computeHash()
+	if err != nil {
+		return AuditEntry{}, err
+	}
+	entry.Hash = hash
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return AuditEntry{}, fmt.Errorf("encode audit entry: %w", err)
+	}
+
+	if _, err := l.w.Write(append(data, '\n')); err != nil {
+		return AuditEntry{}, fmt.Errorf("write audit entry: %w", err)
+	}
+
+	l.entries = append(l.entries, entry)
+	return entry, nil
+}
+
+// Entries returns a copy of all entries of the log in the order they were appended.
+func (l *AuditLog) Entries() []AuditEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]AuditEntry(nil), l.entries...)
+}
+
+// Verify checks that every entry hash matches its content and links to the hash of the previous entry.
+func (l *AuditLog) Verify() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	previousHash := ""
+	for i, entry := range l.entries {
+		if entry.PreviousHash != previousHash {
+			return fmt.Errorf("entry %d: previous hash mismatch: %w", i, ErrAuditChainBroken)
+		}
+
+		hash, err := entry.computeHash()
+		if err != nil {
+			return fmt.Errorf("entry %d: %w", i, err)
+		}
+		if entry.Hash != hash {
+			return fmt.Errorf("entry %d: hash mismatch: %w", i, ErrAuditChainBroken)
+		}
+
+		previousHash = entry.Hash
+	}
+
+	return nil
+}
+
+// CertificateAuditor creates, reissues and revokes certificates on behalf of an operator
+// and records every operation in an audit log.
+type CertificateAuditor[T Content] struct {
+	log         *AuditLog
+	operatorKey babyjub.PrivateKey
+}
+
+// NewCertificateAuditor creates an auditor signing certificates with the operator key and recording into log.
+func NewCertificateAuditor[T Content](log *AuditLog, operatorKey babyjub.PrivateKey) *CertificateAuditor[T] {
+	return &CertificateAuditor[T]{
+		log:         log,
+		operatorKey: operatorKey,
+	}
+}
+
+// Create signs the content for the holder and creates a certificate with a random salt.
+func (a *CertificateAuditor[T]) Create(
+	holderCommitment Hash,
+	content T,
+	expirationDate time.Time,
+) (*Certificate[T], error) {
+	certificate, err := a.issue(holderCommitment, content, expirationDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.record(AuditOperationCreate, certificate.DID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return certificate, nil
+}
+
+// Reissue creates a new certificate with the same holder and content as the previous one,
+// a fresh random salt and the given expiration date. The new certificate is linked to the previous DID.
+func (a *CertificateAuditor[T]) Reissue(previous *Certificate[T], expirationDate time.Time) (*Certificate[T], error) {
+	certificate, err := a.issue(previous.HolderCommitment, previous.Content, expirationDate)
+	if err != nil {
+		return nil, err
+	}
+
+	certificate, err = certificate.LinkToPrevious(previous.DID)
+	if err != nil {
+		return nil, fmt.Errorf("link to previous certificate: %w", err)
+	}
+
+	if err := a.record(AuditOperationReissue, certificate.DID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return certificate, nil
+}
+
+// Revoke creates a revocation record for the certificate.
+func (a *CertificateAuditor[T]) Revoke(certificate *Certificate[T], reason string) (Revocation, error) {
+	revocation := Revocation{
+		LeafHash:  certificate.LeafHash,
+		RevokedAt: time.Now(),
+		Reason:    reason,
+	}
+
+	if err := a.record(AuditOperationRevoke, certificate.DID, revocation.RevokedAt); err != nil {
+		return Revocation{}, err
+	}
+
+	return revocation, nil
+}
+
+func (a *CertificateAuditor[T]) issue(holderCommitment Hash, content T, expirationDate time.Time) (*Certificate[T], error) {
+	contentHash, err := content.Hash()
+	if err != nil {
+		return nil, fmt.Errorf("hash certificate content: %w", err)
+	}
+
+	return newSignedWithRandomSalt(a.operatorKey, holderCommitment, content, contentHash, expirationDate)
+}
+
+func (a *CertificateAuditor[T]) record(operation AuditOperation, did string, timestamp time.Time) error {
+	if _, err := a.log.Append(operation, did, a.operatorKey.Public(), timestamp); err != nil {
+		return fmt.Errorf("record %s: %w", operation, err)
+	}
+
+	return nil
+}
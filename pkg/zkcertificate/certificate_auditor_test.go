@@ -0,0 +1,129 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestCertificateAuditor(t *testing.T) {
+	var buf bytes.Buffer
+	log := zkcertificate.NewAuditLog(&buf)
+
+	operatorKey := babyjub.NewRandPrivKey()
+	auditor := zkcertificate.NewCertificateAuditor[zkcertificate.SimpleJSONContent](log, operatorKey)
+
+	content, err := zkcertificate.SimpleJSON{"name": "John Doe", "age": "30"}.FFEncode()
+	require.NoError(t, err)
+
+	created, err := auditor.Create(zkcertificate.HashFromBigInt(big.NewInt(7)), content, time.Unix(1900000000, 0))
+	require.NoError(t, err)
+
+	reissued, err := auditor.Reissue(created, time.Unix(2000000000, 0))
+	require.NoError(t, err)
+	require.Equal(t, created.DID, reissued.LinkedPreviousDID)
+	require.Equal(t, created.ContentHash, reissued.ContentHash)
+
+	revocation, err := auditor.Revoke(created, "superseded")
+	require.NoError(t, err)
+	require.Equal(t, created.LeafHash, revocation.LeafHash)
+
+	entries := log.Entries()
+	require.Len(t, entries, 3)
+	require.Equal(t, zkcertificate.AuditOperationCreate, entries[0].Operation)
+	require.Equal(t, created.DID, entries[0].CertificateDID)
+	require.Empty(t, entries[0].PreviousHash)
+	require.Equal(t, zkcertificate.AuditOperationReissue, entries[1].Operation)
+	require.Equal(t, reissued.DID, entries[1].CertificateDID)
+	require.Equal(t, entries[0].Hash, entries[1].PreviousHash)
+	require.Equal(t, zkcertificate.AuditOperationRevoke, entries[2].Operation)
+	require.Equal(t, created.DID, entries[2].CertificateDID)
+	require.Equal(t, entries[1].Hash, entries[2].PreviousHash)
+	require.NoError(t, log.Verify())
+
+	restored, err := zkcertificate.ReadAuditLog(bytes.NewReader(buf.Bytes()), &buf)
+	require.NoError(t, err)
+	require.Equal(t, entries, restored.Entries())
+	require.NoError(t, restored.Verify())
+
+	_, err = restored.Append(zkcertificate.AuditOperationRevoke, reissued.DID, operatorKey.Public(), time.Now())
+	require.NoError(t, err)
+	require.NoError(t, restored.Verify())
+	require.Equal(t, 4, strings.Count(buf.String(), "\n"))
+}
+
+func TestAuditLog_Verify(t *testing.T) {
+	var buf bytes.Buffer
+	log := zkcertificate.NewAuditLog(&buf)
+
+	operator := babyjub.NewRandPrivKey()
+	for _, did := range []string{"did:a", "did:b", "did:c"} {
+		_, err := log.Append(zkcertificate.AuditOperationCreate, did, operator.Public(), time.Unix(1700000000, 0))
+		require.NoError(t, err)
+	}
+
+	lines := strings.SplitAfter(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "untouched",
+			data: buf.String(),
+		},
+		{
+			name:    "modified entry",
+			data:    strings.Replace(buf.String(), "did:b", "did:x", 1),
+			wantErr: true,
+		},
+		{
+			name:    "removed entry",
+			data:    lines[0] + lines[2],
+			wantErr: true,
+		},
+		{
+			name:    "reordered entries",
+			data:    lines[1] + lines[0] + lines[2],
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restored, err := zkcertificate.ReadAuditLog(strings.NewReader(tt.data), &bytes.Buffer{})
+			require.NoError(t, err)
+
+			err = restored.Verify()
+			if tt.wantErr {
+				require.ErrorIs(t, err, zkcertificate.ErrAuditChainBroken)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import "encoding/json"
+
+// MarshalBinary implements [encoding.BinaryMarshaler]. The certificate is encoded as JSON.
+func (c *Certificate[T]) MarshalBinary() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler].
+func (c *Certificate[T]) UnmarshalBinary(data []byte) error {
+	var certificate Certificate[T]
+	if err := json.Unmarshal(data, &certificate); err != nil {
+		return err
+	}
+
+	*c = certificate
+	return nil
+}
+
+// GobEncode implements [gob.GobEncoder] by delegating to MarshalBinary.
+func (c *Certificate[T]) GobEncode() ([]byte, error) {
+	return c.MarshalBinary()
+}
+
+// GobDecode implements [gob.GobDecoder] by delegating to UnmarshalBinary.
+func (c *Certificate[T]) GobDecode(data []byte) error {
+	return c.UnmarshalBinary(data)
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler]. It shadows Certificate.MarshalBinary promoted
+// from the embedded certificate, so the registration details and the Merkle proof are encoded as well.
+func (c *IssuedCertificate[T]) MarshalBinary() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler].
+func (c *IssuedCertificate[T]) UnmarshalBinary(data []byte) error {
+	var certificate IssuedCertificate[T]
+	if err := json.Unmarshal(data, &certificate); err != nil {
+		return err
+	}
+
+	*c = certificate
+	return nil
+}
+
+// GobEncode implements [gob.GobEncoder] by delegating to MarshalBinary.
+func (c *IssuedCertificate[T]) GobEncode() ([]byte, error) {
+	return c.MarshalBinary()
+}
+
+// GobDecode implements [gob.GobDecoder] by delegating to UnmarshalBinary.
+func (c *IssuedCertificate[T]) GobDecode(data []byte) error {
+	return c.UnmarshalBinary(data)
+}
@@ -0,0 +1,81 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestCertificate_Gob(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(certificate))
+
+	var decoded zkcertificate.Certificate[zkcertificate.SimpleJSONContent]
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&decoded))
+
+	require.Equal(t, certificate.DID, decoded.DID)
+	require.Equal(t, certificate.LeafHash, decoded.LeafHash)
+	require.Equal(t, certificate.HolderCommitment, decoded.HolderCommitment)
+	require.Equal(t, certificate.Content, decoded.Content)
+	require.Equal(t, certificate.RandomSalt, decoded.RandomSalt)
+	require.Equal(t, certificate.ExpirationDate.Unix(), decoded.ExpirationDate.Unix())
+	require.Zero(t, certificate.Provider.PublicKey.X.Cmp(decoded.Provider.PublicKey.X))
+	require.Zero(t, certificate.Provider.PublicKey.Y.Cmp(decoded.Provider.PublicKey.Y))
+	require.Zero(t, certificate.Provider.Signature.S.Cmp(decoded.Provider.Signature.S))
+	require.Zero(t, certificate.Provider.Signature.R8.X.Cmp(decoded.Provider.Signature.R8.X))
+	require.Zero(t, certificate.Provider.Signature.R8.Y.Cmp(decoded.Provider.Signature.R8.Y))
+}
+
+func TestIssuedCertificate_Gob(t *testing.T) {
+	certificate := makeIssuedCertificate(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(certificate))
+
+	var decoded zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent]
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&decoded))
+
+	require.Equal(t, certificate.DID, decoded.DID)
+	require.True(t, certificate.Provider.Equal(decoded.Provider))
+	require.Equal(t, certificate.Registration, decoded.Registration)
+	require.Equal(t, certificate.MerkleProof, decoded.MerkleProof)
+}
+
+func TestIssuedCertificate_BinaryMarshaler(t *testing.T) {
+	certificate := makeIssuedCertificate(t)
+
+	var marshaler encoding.BinaryMarshaler = certificate
+	data, err := marshaler.MarshalBinary()
+	require.NoError(t, err)
+
+	var decoded zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent]
+	var unmarshaler encoding.BinaryUnmarshaler = &decoded
+	require.NoError(t, unmarshaler.UnmarshalBinary(data))
+
+	require.Equal(t, certificate.DID, decoded.DID)
+	require.True(t, certificate.Provider.Equal(decoded.Provider))
+	require.Equal(t, certificate.Registration, decoded.Registration)
+	require.Equal(t, certificate.MerkleProof, decoded.MerkleProof)
+}
@@ -0,0 +1,113 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import "fmt"
+
+// CertificateErrorKind classifies a CertificateError, so callers can handle specific failure modes.
+type CertificateErrorKind int
+
+const (
+	// InvalidArgument indicates a malformed argument or one that is inconsistent with the certificate.
+	InvalidArgument CertificateErrorKind = iota + 1
+	// InvalidSignature indicates a missing, malformed or not matching provider signature.
+	InvalidSignature
+	// InvalidContentHash indicates certificate content that can not be hashed or does not match its hash.
+	InvalidContentHash
+	// InvalidLeafHash indicates a leaf hash or DID that does not match the certificate fields.
+	InvalidLeafHash
+	// ExpiredCertificate indicates a certificate past its expiration date.
+	ExpiredCertificate
+	// UnknownStandard indicates a certificate standard that is not supported.
+	UnknownStandard
+	// InvalidHolderCommitment indicates a holder commitment that is not a field element.
+	InvalidHolderCommitment
+	// InvalidProviderData indicates a malformed provider public key or signature encoding.
+	InvalidProviderData
+	// InvalidChain indicates certificates that are not correctly linked to their predecessors.
+	InvalidChain
+	// InvalidRegistration indicates invalid registration details of an issued certificate.
+	InvalidRegistration
+	// InvalidMerkleProof indicates a Merkle proof that does not match the certificate or the tree.
+	InvalidMerkleProof
+	// EncodingFailed indicates a failure to encode or decode certificate data.
+	EncodingFailed
+	// HashingFailed indicates a failure to compute a hash, e.g. because of inputs outside the field.
+	HashingFailed
+	// Canceled indicates an operation aborted by its context.
+	Canceled
+	// InvalidContent indicates certificate content rejected by a ContentValidator.
+	InvalidContent
+)
+
+var certificateErrorKindNames = map[CertificateErrorKind]string{
+	InvalidArgument:         "invalid argument",
+	InvalidSignature:        "invalid signature",
+	InvalidContentHash:      "invalid content hash",
+	InvalidLeafHash:         "invalid leaf hash",
+	ExpiredCertificate:      "expired certificate",
+	UnknownStandard:         "unknown standard",
+	InvalidHolderCommitment: "invalid holder commitment",
+	InvalidProviderData:     "invalid provider data",
+	InvalidChain:            "invalid chain",
+	InvalidRegistration:     "invalid registration",
+	InvalidMerkleProof:      "invalid merkle proof",
+	EncodingFailed:          "encoding failed",
+	HashingFailed:           "hashing failed",
+	Canceled:                "canceled",
+	InvalidContent:          "invalid content",
+}
+
+// String implements [fmt.Stringer].
+func (k CertificateErrorKind) String() string {
+	if name, ok := certificateErrorKindNames[k]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("CertificateErrorKind(%d)", int(k))
+}
+
+// CertificateError is the error returned by certificate operations.
+// Use errors.As to inspect its Kind.
+type CertificateError struct {
+	Kind  CertificateErrorKind
+	Msg   string
+	Cause error
+}
+
+// Error implements the error interface.
+func (e *CertificateError) Error() string {
+	if e.Cause == nil {
+		return e.Msg
+	}
+
+	return e.Msg + ": " + e.Cause.Error()
+}
+
+// Unwrap returns the cause of the error.
+func (e *CertificateError) Unwrap() error {
+	return e.Cause
+}
+
+// newCertificateError creates a CertificateError with a formatted message.
+func newCertificateError(kind CertificateErrorKind, format string, args ...interface{}) error {
+	return &CertificateError{Kind: kind, Msg: fmt.Sprintf(format, args...)}
+}
+
+// wrapCertificateError creates a CertificateError with a formatted message caused by the given error.
+func wrapCertificateError(kind CertificateErrorKind, cause error, format string, args ...interface{}) error {
+	return &CertificateError{Kind: kind, Msg: fmt.Sprintf(format, args...), Cause: cause}
+}
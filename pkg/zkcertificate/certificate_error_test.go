@@ -0,0 +1,126 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestCertificateError(t *testing.T) {
+	cause := errors.New("cause")
+
+	err := &zkcertificate.CertificateError{Kind: zkcertificate.InvalidLeafHash, Msg: "message", Cause: cause}
+	require.EqualError(t, err, "message: cause")
+	require.ErrorIs(t, err, cause)
+
+	err = &zkcertificate.CertificateError{Kind: zkcertificate.InvalidLeafHash, Msg: "message"}
+	require.EqualError(t, err, "message")
+
+	require.Equal(t, "invalid leaf hash", zkcertificate.InvalidLeafHash.String())
+	require.Equal(t, "CertificateErrorKind(0)", zkcertificate.CertificateErrorKind(0).String())
+}
+
+func TestCertificateError_kinds(t *testing.T) {
+	certificate, privateKey := makeCertificate(t)
+	otherKey := babyjub.NewRandPrivKey()
+
+	tests := []struct {
+		name string
+		err  func() error
+		kind zkcertificate.CertificateErrorKind
+	}{
+		{
+			name: "invalid signature",
+			err: func() error {
+				_, err := zkcertificate.New(
+					certificate.HolderCommitment,
+					certificate.Content,
+					otherKey.Public(),
+					&certificate.Provider.Signature,
+					1,
+					time.Unix(1700000000, 0),
+				)
+				return err
+			},
+			kind: zkcertificate.InvalidSignature,
+		},
+		{
+			name: "invalid holder commitment",
+			err: func() error {
+				_, err := zkcertificate.New(
+					zkcertificate.HashFromBigInt(constants.Q),
+					certificate.Content,
+					privateKey.Public(),
+					&certificate.Provider.Signature,
+					1,
+					time.Unix(1700000000, 0),
+				)
+				return err
+			},
+			kind: zkcertificate.InvalidHolderCommitment,
+		},
+		{
+			name: "invalid chain",
+			err: func() error {
+				_, err := certificate.LinkToPrevious(certificate.DID)
+				return err
+			},
+			kind: zkcertificate.InvalidChain,
+		},
+		{
+			name: "invalid leaf hash",
+			err: func() error {
+				tampered := certificate.Clone()
+				tampered.LeafHash = zkcertificate.HashFromBigInt(big.NewInt(1))
+				return zkcertificate.VerifyChain([]*zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{tampered})
+			},
+			kind: zkcertificate.InvalidLeafHash,
+		},
+		{
+			name: "canceled",
+			err: func() error {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				_, err := zkcertificate.SignCertificateWithContext(
+					ctx,
+					privateKey,
+					certificate.ContentHash,
+					certificate.HolderCommitment,
+				)
+				return err
+			},
+			kind: zkcertificate.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var certificateError *zkcertificate.CertificateError
+			require.ErrorAs(t, tt.err(), &certificateError)
+			require.Equal(t, tt.kind, certificateError.Kind)
+		})
+	}
+}
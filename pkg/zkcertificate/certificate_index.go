@@ -0,0 +1,185 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ErrCertificateNotFound is returned when a certificate is missing in an index or a store.
+var ErrCertificateNotFound = errors.New("certificate not found")
+
+// CertificateIndex is an in-memory inverted index from content field values to certificates.
+// Content is indexed by the top-level fields of its JSON representation,
+// so only certificates with JSON object content (e.g. KYC) can be indexed.
+type CertificateIndex[T Content] struct {
+	mu           sync.RWMutex
+	fields       map[string]map[string][]string
+	certificates map[string]*IssuedCertificate[T]
+}
+
+type certificateIndexDTO[T Content] struct {
+	Fields       map[string]map[string][]string `json:"fields"`
+	Certificates []*IssuedCertificate[T]        `json:"certificates"`
+}
+
+// NewCertificateIndex creates an empty certificate index.
+func NewCertificateIndex[T Content]() *CertificateIndex[T] {
+	return &CertificateIndex[T]{
+		fields:       make(map[string]map[string][]string),
+		certificates: make(map[string]*IssuedCertificate[T]),
+	}
+}
+
+// AddToIndex indexes every top-level content field of the certificate.
+// String field values are indexed as is, other values by their JSON representation, e.g. "30" or "true".
+func (idx *CertificateIndex[T]) AddToIndex(certificate *IssuedCertificate[T]) error {
+	if certificate.DID == "" {
+		return fmt.Errorf("certificate has no did")
+	}
+
+	values, err := contentFieldValues(certificate.Content)
+	if err != nil {
+		return err
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, ok := idx.certificates[certificate.DID]; ok {
+		return fmt.Errorf("certificate %s is already indexed", certificate.DID)
+	}
+
+	for field, value := range values {
+		if idx.fields[field] == nil {
+			idx.fields[field] = make(map[string][]string)
+		}
+
+		idx.fields[field][value] = append(idx.fields[field][value], certificate.DID)
+	}
+
+	idx.certificates[certificate.DID] = certificate
+	return nil
+}
+
+// LookupByField returns the certificates whose content field has the given value, in the order they were indexed.
+// An empty result is returned if no certificate matches.
+func (idx *CertificateIndex[T]) LookupByField(fieldName, fieldValue string) ([]*IssuedCertificate[T], error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	dids := idx.fields[fieldName][fieldValue]
+	res := make([]*IssuedCertificate[T], 0, len(dids))
+
+	for _, did := range dids {
+		certificate, ok := idx.certificates[did]
+		if !ok {
+			return nil, fmt.Errorf("certificate %s: %w", did, ErrCertificateNotFound)
+		}
+
+		res = append(res, certificate)
+	}
+
+	return res, nil
+}
+
+// Save writes the index together with the indexed certificates to w as JSON.
+func (idx *CertificateIndex[T]) Save(w io.Writer) error {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	dto := certificateIndexDTO[T]{
+		Fields:       idx.fields,
+		Certificates: make([]*IssuedCertificate[T], 0, len(idx.certificates)),
+	}
+	for _, certificate := range idx.certificates {
+		dto.Certificates = append(dto.Certificates, certificate)
+	}
+
+	if err := json.NewEncoder(w).Encode(dto); err != nil {
+		return fmt.Errorf("encode certificate index: %w", err)
+	}
+
+	return nil
+}
+
+// Load replaces the content of the index with the index previously written by Save.
+func (idx *CertificateIndex[T]) Load(r io.Reader) error {
+	var dto certificateIndexDTO[T]
+	if err := json.NewDecoder(r).Decode(&dto); err != nil {
+		return fmt.Errorf("decode certificate index: %w", err)
+	}
+
+	certificates := make(map[string]*IssuedCertificate[T], len(dto.Certificates))
+	for i, certificate := range dto.Certificates {
+		if certificate == nil {
+			return fmt.Errorf("certificate %d is null", i)
+		}
+
+		certificates[certificate.DID] = certificate
+	}
+
+	for field, values := range dto.Fields {
+		for value, dids := range values {
+			for _, did := range dids {
+				if _, ok := certificates[did]; !ok {
+					return fmt.Errorf("field %q value %q: certificate %s: %w", field, value, did, ErrCertificateNotFound)
+				}
+			}
+		}
+	}
+
+	if dto.Fields == nil {
+		dto.Fields = make(map[string]map[string][]string)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.fields = dto.Fields
+	idx.certificates = certificates
+	return nil
+}
+
+// contentFieldValues returns the string representations of the top-level fields of the JSON encoded content.
+func contentFieldValues(content interface{}) (map[string]string, error) {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("encode content: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("content is not a json object: %w", err)
+	}
+
+	values := make(map[string]string, len(fields))
+	for field, raw := range fields {
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			values[field] = s
+			continue
+		}
+
+		values[field] = string(raw)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,136 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestCertificateIndex(t *testing.T) {
+	first := makeKYCIssuedCertificate(t, "DEU", 1)
+	second := makeKYCIssuedCertificate(t, "LIE", 2)
+	third := makeKYCIssuedCertificate(t, "DEU", 3)
+
+	index := zkcertificate.NewCertificateIndex[zkcertificate.KYCContent]()
+	for _, certificate := range []*zkcertificate.IssuedCertificate[zkcertificate.KYCContent]{first, second, third} {
+		require.NoError(t, index.AddToIndex(certificate))
+	}
+	require.Error(t, index.AddToIndex(first))
+
+	country := first.Content.Country.String()
+
+	found, err := index.LookupByField("country", country)
+	require.NoError(t, err)
+	require.Equal(t, []*zkcertificate.IssuedCertificate[zkcertificate.KYCContent]{first, third}, found)
+
+	found, err = index.LookupByField("yearOfBirth", "1989")
+	require.NoError(t, err)
+	require.Len(t, found, 3)
+
+	found, err = index.LookupByField("country", "unknown")
+	require.NoError(t, err)
+	require.Empty(t, found)
+
+	found, err = index.LookupByField("unknown", country)
+	require.NoError(t, err)
+	require.Empty(t, found)
+
+	var buf bytes.Buffer
+	require.NoError(t, index.Save(&buf))
+
+	loaded := zkcertificate.NewCertificateIndex[zkcertificate.KYCContent]()
+	require.NoError(t, loaded.Load(&buf))
+
+	found, err = loaded.LookupByField("country", country)
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	require.Equal(t, first.DID, found[0].DID)
+	require.Equal(t, third.DID, found[1].DID)
+	require.Equal(t, first.Content, found[0].Content)
+}
+
+func TestCertificateIndex_AddToIndex_nonObjectContent(t *testing.T) {
+	index := zkcertificate.NewCertificateIndex[zkcertificate.SimpleJSONContent]()
+	require.Error(t, index.AddToIndex(makeIssuedCertificate(t)))
+}
+
+func TestCertificateIndex_Load_unknownCertificate(t *testing.T) {
+	index := zkcertificate.NewCertificateIndex[zkcertificate.KYCContent]()
+
+	err := index.Load(bytes.NewBufferString(`{"fields":{"country":{"1":["did:unknown"]}},"certificates":[]}`))
+	require.ErrorIs(t, err, zkcertificate.ErrCertificateNotFound)
+}
+
+func TestCertificateIndex_Load_nullCertificate(t *testing.T) {
+	index := zkcertificate.NewCertificateIndex[zkcertificate.KYCContent]()
+
+	err := index.Load(bytes.NewBufferString(`{"fields":{},"certificates":[null]}`))
+	require.ErrorContains(t, err, "certificate 0 is null")
+}
+
+func makeKYCIssuedCertificate(
+	t *testing.T,
+	country string,
+	leafIndex int,
+) *zkcertificate.IssuedCertificate[zkcertificate.KYCContent] {
+	t.Helper()
+
+	content, err := zkcertificate.KYCInputs{
+		Surname:      "Doe",
+		Forename:     "John",
+		YearOfBirth:  1989,
+		MonthOfBirth: 5,
+		DayOfBirth:   28,
+		Citizenship:  country,
+		Country:      country,
+	}.FFEncode()
+	require.NoError(t, err)
+
+	privateKey := babyjub.NewRandPrivKey()
+	holderCommitment := zkcertificate.HashFromBigInt(big.NewInt(7))
+
+	contentHash, err := content.Hash()
+	require.NoError(t, err)
+
+	signature, err := zkcertificate.SignCertificate(privateKey, contentHash, holderCommitment)
+	require.NoError(t, err)
+
+	certificate, err := zkcertificate.New(holderCommitment, content, privateKey.Public(), signature, 1, time.Unix(1900000000, 0))
+	require.NoError(t, err)
+
+	tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+	require.NoError(t, tree.SetLeaf(leafIndex, merkle.TreeNode{Value: uint256.MustFromBig(certificate.LeafHash.BigInt())}))
+
+	proof, err := tree.GetProof(leafIndex)
+	require.NoError(t, err)
+
+	return &zkcertificate.IssuedCertificate[zkcertificate.KYCContent]{
+		Certificate:  *certificate,
+		Registration: zkcertificate.RegistrationDetails{LeafIndex: leafIndex},
+		MerkleProof:  proof,
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import "time"
+
+// CertificateMetrics holds aggregated statistics about a collection of certificates.
+type CertificateMetrics struct {
+	TotalCertificates    int
+	ExpiredCertificates  int
+	RevokedCertificates  int
+	StandardBreakdown    map[Standard]int
+	OldestExpirationDate time.Time
+}
+
+// CollectMetrics computes certificate statistics in a single pass over the given certificates.
+// A certificate is counted as expired if its expiration date is before now, and as revoked
+// if its leaf hash is contained in revocations. Revocations may be nil.
+// OldestExpirationDate is the earliest expiration date among the certificates, or zero if there are none.
+func CollectMetrics[T any](certificates []*Certificate[T], revocations *RevocationSet, now time.Time) CertificateMetrics {
+	metrics := CertificateMetrics{
+		StandardBreakdown: make(map[Standard]int),
+	}
+
+	for _, cert := range certificates {
+		metrics.TotalCertificates++
+		metrics.StandardBreakdown[cert.Standard]++
+
+		expirationDate := time.Time(cert.ExpirationDate)
+		if now.After(expirationDate) {
+			metrics.ExpiredCertificates++
+		}
+
+		if metrics.OldestExpirationDate.IsZero() || expirationDate.Before(metrics.OldestExpirationDate) {
+			metrics.OldestExpirationDate = expirationDate
+		}
+
+		if revocations != nil && revocations.Contains(cert.LeafHash) {
+			metrics.RevokedCertificates++
+		}
+	}
+
+	return metrics
+}
@@ -0,0 +1,69 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func makeMetricsCertificates(t *testing.T) []*zkcertificate.Certificate[zkcertificate.SimpleJSONContent] {
+	t.Helper()
+
+	certificate, _ := makeCertificate(t)
+
+	expired := certificate.Clone()
+	expired.LeafHash = zkcertificate.HashFromBigInt(big.NewInt(1))
+	expired.ExpirationDate = zkcertificate.Timestamp(time.Unix(1600000000, 0))
+
+	valid := certificate.Clone()
+	valid.LeafHash = zkcertificate.HashFromBigInt(big.NewInt(2))
+	valid.ExpirationDate = zkcertificate.Timestamp(time.Unix(1900000000, 0))
+
+	kyc := certificate.Clone()
+	kyc.LeafHash = zkcertificate.HashFromBigInt(big.NewInt(3))
+	kyc.Standard = zkcertificate.StandardKYC
+	kyc.ExpirationDate = zkcertificate.Timestamp(time.Unix(1900000000, 0))
+
+	return []*zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{expired, valid, kyc}
+}
+
+func TestCollectMetrics(t *testing.T) {
+	certificates := makeMetricsCertificates(t)
+	revocations := zkcertificate.NewRevocationSet(zkcertificate.Revocation{LeafHash: certificates[1].LeafHash})
+
+	metrics := zkcertificate.CollectMetrics(certificates, revocations, time.Unix(1800000000, 0))
+	require.Equal(t, 3, metrics.TotalCertificates)
+	require.Equal(t, 1, metrics.ExpiredCertificates)
+	require.Equal(t, 1, metrics.RevokedCertificates)
+	require.Equal(t, map[zkcertificate.Standard]int{
+		zkcertificate.StandardSimpleJSON: 2,
+		zkcertificate.StandardKYC:        1,
+	}, metrics.StandardBreakdown)
+	require.Equal(t, int64(1600000000), metrics.OldestExpirationDate.Unix())
+}
+
+func TestCollectMetrics_empty(t *testing.T) {
+	metrics := zkcertificate.CollectMetrics[zkcertificate.SimpleJSONContent](nil, nil, time.Now())
+	require.Zero(t, metrics.TotalCertificates)
+	require.Empty(t, metrics.StandardBreakdown)
+	require.True(t, metrics.OldestExpirationDate.IsZero())
+}
@@ -0,0 +1,195 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/iden3/go-iden3-crypto/poseidon"
+)
+
+// maxMultiProviders is the maximum amount of providers that fit into a single Poseidon hash
+// together with the content hash, holder commitment, salt and expiration date.
+const maxMultiProviders = 12
+
+// MultiProviderCertificate represents a zero knowledge certificate co-signed by several providers.
+// The certificate is considered valid if at least Threshold of the stored provider signatures
+// are valid and made by distinct providers.
+type MultiProviderCertificate[T any] struct {
+	HolderCommitment Hash           `json:"holderCommitment"`
+	LeafHash         Hash           `json:"leafHash"`
+	DID              string         `json:"did"`
+	Standard         Standard       `json:"zkCertStandard"`
+	Content          T              `json:"content"`
+	ContentHash      Hash           `json:"contentHash"`
+	ExpirationDate   Timestamp      `json:"expirationDate"`
+	Providers        []ProviderData `json:"providersData"`
+	Threshold        int            `json:"threshold"`
+	RandomSalt       int64          `json:"randomSalt"`
+}
+
+// NewMultiProvider creates a new multi-provider certificate instance with the provided parameters and content.
+// It validates the holder commitment, computes the content hash, verifies that at least threshold providers
+// signed the content, and generates a combined leaf hash.
+func NewMultiProvider[T Content](
+	holderCommitment Hash,
+	content T,
+	providers []ProviderData,
+	threshold int,
+	salt int64,
+	expirationDate time.Time,
+) (*MultiProviderCertificate[T], error) {
+	if err := ValidateHolderCommitment(holderCommitment); err != nil {
+		return nil, wrapCertificateError(InvalidHolderCommitment, err, "invalid holder commitment")
+	}
+
+	contentHash, err := content.Hash()
+	if err != nil {
+		return nil, wrapCertificateError(InvalidContentHash, err, "hash certificate content")
+	}
+
+	standard := content.Standard()
+
+	certificate := &MultiProviderCertificate[T]{
+		HolderCommitment: holderCommitment,
+		Standard:         standard,
+		Content:          content,
+		ContentHash:      contentHash,
+		ExpirationDate:   Timestamp(expirationDate),
+		Providers:        providers,
+		Threshold:        threshold,
+		RandomSalt:       salt,
+	}
+
+	if err := VerifyMultiProvider(certificate); err != nil {
+		return nil, err
+	}
+
+	leafHash, err := MultiProviderLeafHash(contentHash, providers, holderCommitment, salt, expirationDate)
+	if err != nil {
+		return nil, wrapCertificateError(HashingFailed, err, "compute leaf hash")
+	}
+
+	certificate.LeafHash = leafHash
+	certificate.DID = DID(standard, leafHash)
+
+	return certificate, nil
+}
+
+// VerifyMultiProvider checks that at least cert.Threshold of the stored provider signatures
+// are valid for the certificate content and holder commitment. Signatures made with the same
+// public key are counted only once. Incomplete provider data is rejected.
+func VerifyMultiProvider[T any](cert *MultiProviderCertificate[T]) error {
+	if cert.Threshold < 1 {
+		return newCertificateError(InvalidArgument, "invalid threshold %d", cert.Threshold)
+	}
+
+	if len(cert.Providers) < cert.Threshold {
+		return newCertificateError(
+			InvalidArgument,
+			"threshold %d exceeds providers amount %d",
+			cert.Threshold,
+			len(cert.Providers),
+		)
+	}
+
+	validSigners := make(map[[32]byte]struct{}, len(cert.Providers))
+
+	for i, provider := range cert.Providers {
+		if err := provider.validate(); err != nil {
+			return wrapCertificateError(InvalidProviderData, err, "provider %d", i)
+		}
+
+		signatureValid, err := VerifySignature(
+			&provider.PublicKey,
+			cert.ContentHash,
+			cert.HolderCommitment,
+			&provider.Signature,
+		)
+		if err != nil {
+			return wrapCertificateError(InvalidSignature, err, "verify signature of provider %d", i)
+		}
+
+		if signatureValid {
+			validSigners[provider.PublicKey.Compress()] = struct{}{}
+		}
+	}
+
+	if len(validSigners) < cert.Threshold {
+		return newCertificateError(
+			InvalidSignature,
+			"only %d of required %d valid signatures",
+			len(validSigners),
+			cert.Threshold,
+		)
+	}
+
+	return nil
+}
+
+// MultiProviderLeafHash computes the leaf hash of a multi-provider certificate.
+//
+// Public keys and signatures of every provider are first hashed together as
+// Poseidon(Ax, Ay, S, R8x, R8y), then the resulting provider hashes are combined with the rest
+// of the certificate as Poseidon(contentHash, providerHash_1, ..., providerHash_N, commitmentHash, salt, expirationDate).
+func MultiProviderLeafHash(
+	contentHash Hash,
+	providers []ProviderData,
+	commitmentHash Hash,
+	salt int64,
+	expirationDate time.Time,
+) (Hash, error) {
+	if len(providers) == 0 || len(providers) > maxMultiProviders {
+		return Hash{}, newCertificateError(
+			InvalidArgument,
+			"invalid providers amount %d, max %d",
+			len(providers),
+			maxMultiProviders,
+		)
+	}
+
+	inputs := make([]*big.Int, 0, len(providers)+4)
+	inputs = append(inputs, contentHash.BigInt())
+
+	for i, provider := range providers {
+		if err := provider.validate(); err != nil {
+			return Hash{}, wrapCertificateError(InvalidProviderData, err, "provider %d", i)
+		}
+
+		providerHash, err := poseidon.Hash([]*big.Int{
+			provider.PublicKey.X,
+			provider.PublicKey.Y,
+			provider.Signature.S,
+			provider.Signature.R8.X,
+			provider.Signature.R8.Y,
+		})
+		if err != nil {
+			return Hash{}, wrapCertificateError(HashingFailed, err, "hash provider %d", i)
+		}
+
+		inputs = append(inputs, providerHash)
+	}
+
+	inputs = append(inputs, commitmentHash.BigInt(), big.NewInt(salt), big.NewInt(expirationDate.Unix()))
+
+	hash, err := poseidon.Hash(inputs)
+	if err != nil {
+		return Hash{}, wrapCertificateError(HashingFailed, err, "compute hash")
+	}
+
+	return HashFromBigInt(hash), nil
+}
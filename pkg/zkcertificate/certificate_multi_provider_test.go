@@ -0,0 +1,188 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestNewMultiProvider(t *testing.T) {
+	content := zkcertificate.SimpleJSONContent{zkcertificate.HashFromBigInt(big.NewInt(42))}
+	holderCommitment := zkcertificate.HashFromBigInt(big.NewInt(7))
+	providers := makeProviders(t, content, holderCommitment, 3)
+
+	certificate, err := zkcertificate.NewMultiProvider(holderCommitment, content, providers, 2, 1, time.Unix(1700000000, 0))
+	require.NoError(t, err)
+	require.Equal(t, zkcertificate.StandardSimpleJSON, certificate.Standard)
+	require.Equal(t, zkcertificate.DID(certificate.Standard, certificate.LeafHash), certificate.DID)
+
+	leafHash, err := zkcertificate.MultiProviderLeafHash(
+		certificate.ContentHash,
+		providers,
+		holderCommitment,
+		1,
+		time.Unix(1700000000, 0),
+	)
+	require.NoError(t, err)
+	require.Equal(t, leafHash, certificate.LeafHash)
+}
+
+func TestVerifyMultiProvider(t *testing.T) {
+	content := zkcertificate.SimpleJSONContent{zkcertificate.HashFromBigInt(big.NewInt(42))}
+	holderCommitment := zkcertificate.HashFromBigInt(big.NewInt(7))
+
+	certificate, err := zkcertificate.NewMultiProvider(
+		holderCommitment,
+		content,
+		makeProviders(t, content, holderCommitment, 3),
+		2,
+		1,
+		time.Unix(1700000000, 0),
+	)
+	require.NoError(t, err)
+
+	certificate.Providers[0].Signature.S = big.NewInt(1)
+	require.NoError(t, zkcertificate.VerifyMultiProvider(certificate))
+
+	certificate.Providers[1].Signature.S = big.NewInt(1)
+	require.Error(t, zkcertificate.VerifyMultiProvider(certificate))
+}
+
+func TestVerifyMultiProvider_duplicateSigner(t *testing.T) {
+	content := zkcertificate.SimpleJSONContent{zkcertificate.HashFromBigInt(big.NewInt(42))}
+	holderCommitment := zkcertificate.HashFromBigInt(big.NewInt(7))
+	providers := makeProviders(t, content, holderCommitment, 1)
+
+	_, err := zkcertificate.NewMultiProvider(
+		holderCommitment,
+		content,
+		append(providers, providers[0]),
+		2,
+		1,
+		time.Unix(1700000000, 0),
+	)
+	require.Error(t, err)
+}
+
+func TestNewMultiProvider_invalid(t *testing.T) {
+	content := zkcertificate.SimpleJSONContent{zkcertificate.HashFromBigInt(big.NewInt(42))}
+	holderCommitment := zkcertificate.HashFromBigInt(big.NewInt(7))
+
+	tests := []struct {
+		name             string
+		holderCommitment zkcertificate.Hash
+		modify           func(providers []zkcertificate.ProviderData)
+		kind             zkcertificate.CertificateErrorKind
+	}{
+		{
+			name:             "holder commitment out of field",
+			holderCommitment: zkcertificate.HashFromBigInt(constants.Q),
+			modify:           func([]zkcertificate.ProviderData) {},
+			kind:             zkcertificate.InvalidHolderCommitment,
+		},
+		{
+			name:             "missing public key",
+			holderCommitment: holderCommitment,
+			modify: func(providers []zkcertificate.ProviderData) {
+				providers[1].PublicKey = babyjub.PublicKey{}
+			},
+			kind: zkcertificate.InvalidProviderData,
+		},
+		{
+			name:             "missing signature",
+			holderCommitment: holderCommitment,
+			modify: func(providers []zkcertificate.ProviderData) {
+				providers[1].Signature = babyjub.Signature{}
+			},
+			kind: zkcertificate.InvalidProviderData,
+		},
+		{
+			name:             "missing r8 coordinate",
+			holderCommitment: holderCommitment,
+			modify: func(providers []zkcertificate.ProviderData) {
+				providers[1].Signature.R8 = &babyjub.Point{}
+			},
+			kind: zkcertificate.InvalidProviderData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			providers := makeProviders(t, content, holderCommitment, 2)
+			tt.modify(providers)
+
+			_, err := zkcertificate.NewMultiProvider(tt.holderCommitment, content, providers, 1, 1, time.Unix(1700000000, 0))
+
+			var certificateError *zkcertificate.CertificateError
+			require.ErrorAs(t, err, &certificateError)
+			require.Equal(t, tt.kind, certificateError.Kind)
+		})
+	}
+}
+
+func TestMultiProviderLeafHash_incompleteProvider(t *testing.T) {
+	content := zkcertificate.SimpleJSONContent{zkcertificate.HashFromBigInt(big.NewInt(42))}
+	holderCommitment := zkcertificate.HashFromBigInt(big.NewInt(7))
+	providers := makeProviders(t, content, holderCommitment, 2)
+	providers[0].Signature.R8 = nil
+
+	_, err := zkcertificate.MultiProviderLeafHash(
+		zkcertificate.HashFromBigInt(big.NewInt(1)),
+		providers,
+		holderCommitment,
+		1,
+		time.Unix(1700000000, 0),
+	)
+
+	var certificateError *zkcertificate.CertificateError
+	require.ErrorAs(t, err, &certificateError)
+	require.Equal(t, zkcertificate.InvalidProviderData, certificateError.Kind)
+}
+
+func makeProviders(
+	t *testing.T,
+	content zkcertificate.Content,
+	holderCommitment zkcertificate.Hash,
+	amount int,
+) []zkcertificate.ProviderData {
+	t.Helper()
+
+	contentHash, err := content.Hash()
+	require.NoError(t, err)
+
+	providers := make([]zkcertificate.ProviderData, amount)
+	for i := range providers {
+		privateKey := babyjub.NewRandPrivKey()
+
+		signature, err := zkcertificate.SignCertificate(privateKey, contentHash, holderCommitment)
+		require.NoError(t, err)
+
+		providers[i] = zkcertificate.ProviderData{
+			PublicKey: *privateKey.Public(),
+			Signature: *signature,
+		}
+	}
+
+	return providers
+}
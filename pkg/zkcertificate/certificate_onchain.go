@@ -0,0 +1,88 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/contracts"
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
+)
+
+// EthClientInterface is the part of an Ethereum client used to query the registry contract.
+// It is implemented by *ethclient.Client.
+type EthClientInterface interface {
+	bind.ContractCaller
+	bind.ContractFilterer
+}
+
+// CertificateOnChainStatus represents the state of a certificate in the registry contract.
+type CertificateOnChainStatus struct {
+	IsRegistered bool
+	IsRevoked    bool
+	CurrentRoot  merkle.TreeNode
+}
+
+// OnChainStatus queries the registry contract for the current state of the certificate.
+// The certificate is registered if the registry maps its leaf hash to a guardian,
+// and revoked if the registry emitted a revocation event for its leaf hash.
+// The registry state may differ from the local registration details, e.g. after a revocation.
+func (c *IssuedCertificate[T]) OnChainStatus(ctx context.Context, client EthClientInterface) (CertificateOnChainStatus, error) {
+	caller, err := contracts.NewZkCertificateRegistryCaller(c.Registration.Address, client)
+	if err != nil {
+		return CertificateOnChainStatus{}, fmt.Errorf("bind registry contract: %w", err)
+	}
+
+	filterer, err := contracts.NewZkCertificateRegistryFilterer(c.Registration.Address, client)
+	if err != nil {
+		return CertificateOnChainStatus{}, fmt.Errorf("bind registry contract: %w", err)
+	}
+
+	leafHash := c.LeafHash.Bytes32()
+	opts := &bind.CallOpts{Context: ctx}
+
+	guardian, err := caller.ZkCertificateToGuardian(opts, leafHash)
+	if err != nil {
+		return CertificateOnChainStatus{}, fmt.Errorf("retrieve certificate guardian: %w", err)
+	}
+
+	root, err := caller.MerkleRoot(opts)
+	if err != nil {
+		return CertificateOnChainStatus{}, fmt.Errorf("retrieve merkle root: %w", err)
+	}
+
+	revocations, err := filterer.FilterZkCertificateRevocation(&bind.FilterOpts{Context: ctx}, [][32]byte{leafHash}, nil)
+	if err != nil {
+		return CertificateOnChainStatus{}, fmt.Errorf("filter revocation events: %w", err)
+	}
+	defer revocations.Close()
+
+	isRevoked := revocations.Next()
+	if err := revocations.Error(); err != nil {
+		return CertificateOnChainStatus{}, fmt.Errorf("iterate revocation events: %w", err)
+	}
+
+	return CertificateOnChainStatus{
+		IsRegistered: guardian != (common.Address{}),
+		IsRevoked:    isRevoked,
+		CurrentRoot:  merkle.TreeNode{Value: new(uint256.Int).SetBytes32(root[:])},
+	}, nil
+}
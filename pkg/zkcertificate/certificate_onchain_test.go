@@ -0,0 +1,172 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/contracts"
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+// mockEthClient answers registry contract calls from in-memory state.
+type mockEthClient struct {
+	t         *testing.T
+	guardians map[[32]byte]common.Address
+	root      [32]byte
+	logs      []types.Log
+}
+
+var _ zkcertificate.EthClientInterface = (*mockEthClient)(nil)
+
+func (m *mockEthClient) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return []byte{0x01}, nil
+}
+
+func (m *mockEthClient) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	registryABI, err := contracts.ZkCertificateRegistryMetaData.GetAbi()
+	require.NoError(m.t, err)
+
+	method, err := registryABI.MethodById(call.Data[:4])
+	require.NoError(m.t, err)
+
+	switch method.Name {
+	case "ZkCertificateToGuardian":
+		args, err := method.Inputs.Unpack(call.Data[4:])
+		require.NoError(m.t, err)
+
+		return method.Outputs.Pack(m.guardians[args[0].([32]byte)])
+	case "merkleRoot":
+		return method.Outputs.Pack(m.root)
+	default:
+		m.t.Fatalf("unexpected call of %s", method.Name)
+		return nil, nil
+	}
+}
+
+func (m *mockEthClient) FilterLogs(_ context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	var res []types.Log
+
+	for _, log := range m.logs {
+		if matchesTopics(log.Topics, query.Topics) {
+			res = append(res, log)
+		}
+	}
+
+	return res, nil
+}
+
+func (m *mockEthClient) SubscribeFilterLogs(
+	context.Context,
+	ethereum.FilterQuery,
+	chan<- types.Log,
+) (ethereum.Subscription, error) {
+	m.t.Fatal("unexpected subscription")
+	return nil, nil
+}
+
+func matchesTopics(topics []common.Hash, query [][]common.Hash) bool {
+	for i, alternatives := range query {
+		if len(alternatives) == 0 {
+			continue
+		}
+
+		if i >= len(topics) || !containsHash(alternatives, topics[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsHash(hashes []common.Hash, hash common.Hash) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestIssuedCertificate_OnChainStatus(t *testing.T) {
+	guardian := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	root := uint256.NewInt(12345).Bytes32()
+
+	registered := makeIssuedCertificate(t)
+	revoked := makeIssuedCertificate(t)
+	unknown := makeIssuedCertificate(t)
+
+	index := uint256.NewInt(1).Bytes32()
+	client := &mockEthClient{
+		t: t,
+		guardians: map[[32]byte]common.Address{
+			registered.LeafHash.Bytes32(): guardian,
+		},
+		root: root,
+		logs: []types.Log{{
+			Address: revoked.Registration.Address,
+			Topics: []common.Hash{
+				crypto.Keccak256Hash([]byte("zkCertificateRevocation(bytes32,address,uint256)")),
+				revoked.LeafHash.Bytes32(),
+				common.BytesToHash(guardian.Bytes()),
+			},
+			Data: index[:],
+		}},
+	}
+
+	currentRoot := merkle.TreeNode{Value: new(uint256.Int).SetBytes32(root[:])}
+
+	tests := []struct {
+		name        string
+		certificate *zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent]
+		expected    zkcertificate.CertificateOnChainStatus
+	}{
+		{
+			name:        "registered",
+			certificate: registered,
+			expected:    zkcertificate.CertificateOnChainStatus{IsRegistered: true, CurrentRoot: currentRoot},
+		},
+		{
+			name:        "revoked",
+			certificate: revoked,
+			expected:    zkcertificate.CertificateOnChainStatus{IsRevoked: true, CurrentRoot: currentRoot},
+		},
+		{
+			name:        "unknown",
+			certificate: unknown,
+			expected:    zkcertificate.CertificateOnChainStatus{CurrentRoot: currentRoot},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := tt.certificate.OnChainStatus(context.Background(), client)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, status)
+		})
+	}
+}
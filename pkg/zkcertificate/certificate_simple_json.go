@@ -90,3 +90,17 @@ func (c SimpleJSONContent) Hash() (Hash, error) {
 
 	return HashFromBigInt(hash), nil
 }
+
+// Clone returns a deep copy of the SimpleJSONContent instance.
+func (c SimpleJSONContent) Clone() SimpleJSONContent {
+	if c == nil {
+		return nil
+	}
+
+	res := make(SimpleJSONContent, len(c))
+	for i, hash := range c {
+		res[i] = cloneHash(hash)
+	}
+
+	return res
+}
@@ -0,0 +1,44 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import "time"
+
+// CertificateSummary holds non-sensitive certificate metadata suitable for public API responses.
+// It deliberately omits the certificate content, holder commitment and random salt.
+type CertificateSummary struct {
+	DID                   string    `json:"did"`
+	Standard              Standard  `json:"zkCertStandard"`
+	ExpirationDate        Timestamp `json:"expirationDate"`
+	IsExpired             bool      `json:"isExpired"`
+	ProviderPublicKeyHash Hash      `json:"providerPublicKeyHash"`
+	LeafHash              Hash      `json:"leafHash"`
+}
+
+// Summarize extracts non-sensitive metadata of the certificate.
+// ProviderPublicKeyHash is left zero if the provider public key is incomplete.
+func (c *Certificate[T]) Summarize() CertificateSummary {
+	providerPublicKeyHash, _ := c.ProviderPublicKeyHash()
+
+	return CertificateSummary{
+		DID:                   c.DID,
+		Standard:              c.Standard,
+		ExpirationDate:        c.ExpirationDate,
+		IsExpired:             time.Now().After(time.Time(c.ExpirationDate)),
+		ProviderPublicKeyHash: providerPublicKeyHash,
+		LeafHash:              c.LeafHash,
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestCertificate_Summarize(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	summary := certificate.Summarize()
+	require.Equal(t, certificate.DID, summary.DID)
+	require.Equal(t, certificate.Standard, summary.Standard)
+	require.Equal(t, certificate.LeafHash, summary.LeafHash)
+	require.Equal(t, int64(1700000000), summary.ExpirationDate.Unix())
+	require.True(t, summary.IsExpired)
+
+	providerPublicKeyHash, err := certificate.ProviderPublicKeyHash()
+	require.NoError(t, err)
+	require.Equal(t, providerPublicKeyHash, summary.ProviderPublicKeyHash)
+
+	certificate.ExpirationDate = zkcertificate.Timestamp(time.Now().Add(time.Hour))
+	require.False(t, certificate.Summarize().IsExpired)
+}
+
+func TestCertificateSummary_JSON(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	data, err := json.Marshal(certificate.Summarize())
+	require.NoError(t, err)
+
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &fields))
+	require.NotContains(t, fields, "content")
+	require.NotContains(t, fields, "holderCommitment")
+	require.NotContains(t, fields, "randomSalt")
+	require.Contains(t, fields, "did")
+}
@@ -0,0 +1,68 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/iden3/go-iden3-crypto/babyjub"
+)
+
+// CertificateTemplate issues certificates with the same content for many holders.
+// Only the holder commitment and the expiration date vary between rendered certificates.
+// Content providing a Clone() T method is copied for the template and for every rendered certificate,
+// so they can be modified independently, see Certificate.Clone.
+type CertificateTemplate[T Content] struct {
+	content       T
+	contentHash   Hash
+	providerKey   babyjub.PrivateKey
+	defaultExpiry time.Duration
+}
+
+// NewCertificateTemplate creates a template for the given content signed with the provider key.
+// The default expiry is used by Render when no expiration date is given, it must be positive.
+func NewCertificateTemplate[T Content](
+	content T,
+	providerKey babyjub.PrivateKey,
+	defaultExpiry time.Duration,
+) (*CertificateTemplate[T], error) {
+	if defaultExpiry <= 0 {
+		return nil, fmt.Errorf("default expiry must be positive")
+	}
+
+	contentHash, err := content.Hash()
+	if err != nil {
+		return nil, fmt.Errorf("hash certificate content: %w", err)
+	}
+
+	return &CertificateTemplate[T]{
+		content:       cloneContent(content),
+		contentHash:   contentHash,
+		providerKey:   providerKey,
+		defaultExpiry: defaultExpiry,
+	}, nil
+}
+
+// Render signs the template content for the given holder and creates a certificate with a random salt.
+// If expiration is zero, the certificate expires after the default expiry from now.
+func (t *CertificateTemplate[T]) Render(holderCommitment Hash, expiration time.Time) (*Certificate[T], error) {
+	if expiration.IsZero() {
+		expiration = time.Now().Add(t.defaultExpiry)
+	}
+
+	return newSignedWithRandomSalt(t.providerKey, holderCommitment, cloneContent(t.content), t.contentHash, expiration)
+}
@@ -0,0 +1,89 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestCertificateTemplate_Render(t *testing.T) {
+	privateKey := babyjub.NewRandPrivKey()
+
+	content, err := zkcertificate.SimpleJSON{"name": "John Doe", "age": "30"}.FFEncode()
+	require.NoError(t, err)
+
+	template, err := zkcertificate.NewCertificateTemplate(content, privateKey, 24*time.Hour)
+	require.NoError(t, err)
+
+	expiration := time.Unix(1900000000, 0)
+
+	first, err := template.Render(zkcertificate.HashFromBigInt(big.NewInt(7)), expiration)
+	require.NoError(t, err)
+	require.Equal(t, expiration.Unix(), time.Time(first.ExpirationDate).Unix())
+
+	second, err := template.Render(zkcertificate.HashFromBigInt(big.NewInt(8)), time.Time{})
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(24*time.Hour), time.Time(second.ExpirationDate), time.Minute)
+
+	require.Equal(t, first.ContentHash, second.ContentHash)
+	require.NotEqual(t, first.LeafHash, second.LeafHash)
+
+	for _, certificate := range []*zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{first, second} {
+		isValid, err := zkcertificate.VerifySignature(
+			privateKey.Public(),
+			certificate.ContentHash,
+			certificate.HolderCommitment,
+			&certificate.Provider.Signature,
+		)
+		require.NoError(t, err)
+		require.True(t, isValid)
+	}
+}
+
+func TestCertificateTemplate_Render_independentContent(t *testing.T) {
+	content, err := zkcertificate.SimpleJSON{"name": "John Doe", "age": "30"}.FFEncode()
+	require.NoError(t, err)
+
+	template, err := zkcertificate.NewCertificateTemplate(content, babyjub.NewRandPrivKey(), 24*time.Hour)
+	require.NoError(t, err)
+
+	first, err := template.Render(zkcertificate.HashFromBigInt(big.NewInt(7)), time.Time{})
+	require.NoError(t, err)
+
+	second, err := template.Render(zkcertificate.HashFromBigInt(big.NewInt(8)), time.Time{})
+	require.NoError(t, err)
+
+	expected := second.Content.Clone()
+	first.Content[0] = zkcertificate.HashFromBigInt(big.NewInt(1))
+	content[1] = zkcertificate.HashFromBigInt(big.NewInt(2))
+
+	require.Equal(t, expected, second.Content)
+}
+
+func TestNewCertificateTemplate_invalidExpiry(t *testing.T) {
+	content, err := zkcertificate.SimpleJSON{"name": "John Doe"}.FFEncode()
+	require.NoError(t, err)
+
+	_, err = zkcertificate.NewCertificateTemplate(content, babyjub.NewRandPrivKey(), 0)
+	require.Error(t, err)
+}
@@ -16,12 +16,23 @@
 package zkcertificate_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
 	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-iden3-crypto/poseidon"
 	"github.com/stretchr/testify/require"
 
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
 	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
 )
 
@@ -43,3 +54,898 @@ func TestSignCertificate(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, isValid)
 }
+
+func TestSignCertificateWithContext(t *testing.T) {
+	privateKey := babyjub.NewRandPrivKey()
+	contentHash := zkcertificate.HashFromBigInt(big.NewInt(1))
+	commitmentHash := zkcertificate.HashFromBigInt(big.NewInt(2))
+
+	signature, err := zkcertificate.SignCertificateWithContext(context.Background(), privateKey, contentHash, commitmentHash)
+	require.NoError(t, err)
+
+	isValid, err := zkcertificate.VerifySignature(privateKey.Public(), contentHash, commitmentHash, signature)
+	require.NoError(t, err)
+	require.True(t, isValid)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = zkcertificate.SignCertificateWithContext(ctx, privateKey, contentHash, commitmentHash)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCertificate_ContentJSON(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	data, err := certificate.ContentJSON()
+	require.NoError(t, err)
+
+	var content zkcertificate.SimpleJSONContent
+	require.NoError(t, json.Unmarshal(data, &content))
+	require.Equal(t, certificate.Content, content)
+}
+
+func TestProviderData_CompressedPublicKey(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	compressed := certificate.Provider.CompressedPublicKey()
+
+	publicKey, err := zkcertificate.DecompressPublicKey(compressed)
+	require.NoError(t, err)
+	require.Equal(t, certificate.Provider.PublicKey.X, publicKey.X)
+	require.Equal(t, certificate.Provider.PublicKey.Y, publicKey.Y)
+}
+
+func TestDecompressPublicKey_invalid(t *testing.T) {
+	var compressed [32]byte
+	for i := range compressed {
+		compressed[i] = 0xff
+	}
+
+	_, err := zkcertificate.DecompressPublicKey(compressed)
+	require.Error(t, err)
+}
+
+func TestCertificate_WithContent(t *testing.T) {
+	certificate, privateKey := makeCertificate(t)
+
+	newContent, err := zkcertificate.SimpleJSON{"name": "John Doe", "age": "31"}.FFEncode()
+	require.NoError(t, err)
+
+	updated, err := certificate.WithContent(newContent, privateKey, 2)
+	require.NoError(t, err)
+	require.Equal(t, newContent, updated.Content)
+	require.Equal(t, certificate.HolderCommitment, updated.HolderCommitment)
+	require.Equal(t, certificate.ExpirationDate, updated.ExpirationDate)
+	require.NotEqual(t, certificate.LeafHash, updated.LeafHash)
+
+	isValid, err := zkcertificate.VerifySignature(
+		&updated.Provider.PublicKey,
+		updated.ContentHash,
+		updated.HolderCommitment,
+		&updated.Provider.Signature,
+	)
+	require.NoError(t, err)
+	require.True(t, isValid)
+}
+
+func TestCertificate_WithContent_sameContent(t *testing.T) {
+	certificate, privateKey := makeCertificate(t)
+
+	_, err := certificate.WithContent(certificate.Content, privateKey, 2)
+	require.Error(t, err)
+}
+
+func TestCertificate_WithContent_otherProvider(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	newContent, err := zkcertificate.SimpleJSON{"name": "John Doe", "age": "31"}.FFEncode()
+	require.NoError(t, err)
+
+	_, err = certificate.WithContent(newContent, babyjub.NewRandPrivKey(), 2)
+	require.Error(t, err)
+}
+
+func TestCertificate_String(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	expected := `Certificate{DID: "` + certificate.DID + `", Standard: "gip2", Expiration: "2023-11-14T22:13:20Z", Expired: true}`
+	require.Equal(t, expected, certificate.String())
+	require.Equal(t, expected, fmt.Sprint(certificate))
+}
+
+func TestCertificate_LinkToPrevious(t *testing.T) {
+	previous, _ := makeCertificate(t)
+	certificate, _ := makeCertificate(t)
+
+	linked, err := certificate.LinkToPrevious(previous.DID)
+	require.NoError(t, err)
+	require.Equal(t, previous.DID, linked.LinkedPreviousDID)
+	require.NotEqual(t, certificate.LeafHash, linked.LeafHash)
+	require.Equal(t, zkcertificate.DID(linked.Standard, linked.LeafHash), linked.DID)
+	require.Empty(t, certificate.LinkedPreviousDID)
+
+	_, err = certificate.LinkToPrevious("invalid")
+	require.Error(t, err)
+}
+
+func TestLinkedLeafHash(t *testing.T) {
+	previous, _ := makeCertificate(t)
+	certificate, _ := makeCertificate(t)
+
+	linked, err := certificate.LinkToPrevious(previous.DID)
+	require.NoError(t, err)
+
+	leafHash, err := zkcertificate.LinkedLeafHash(
+		certificate.ContentHash,
+		&certificate.Provider.PublicKey,
+		&certificate.Provider.Signature,
+		certificate.HolderCommitment,
+		certificate.RandomSalt,
+		time.Time(certificate.ExpirationDate),
+		previous.DID,
+	)
+	require.NoError(t, err)
+	require.Equal(t, linked.LeafHash, leafHash)
+
+	tests := []struct {
+		name        string
+		contentHash zkcertificate.Hash
+		previousDID string
+	}{
+		{name: "content hash not in field", contentHash: zkcertificate.HashFromBigInt(constants.Q), previousDID: previous.DID},
+		{name: "empty previous did", contentHash: certificate.ContentHash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := zkcertificate.LinkedLeafHash(
+				tt.contentHash,
+				&certificate.Provider.PublicKey,
+				&certificate.Provider.Signature,
+				certificate.HolderCommitment,
+				certificate.RandomSalt,
+				time.Time(certificate.ExpirationDate),
+				tt.previousDID,
+			)
+
+			var certificateError *zkcertificate.CertificateError
+			require.ErrorAs(t, err, &certificateError)
+			require.Equal(t, zkcertificate.InvalidArgument, certificateError.Kind)
+		})
+	}
+}
+
+func TestCertificate_WithSalt(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	salted, err := certificate.WithSalt(2)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), salted.RandomSalt)
+	require.Equal(t, int64(1), certificate.RandomSalt)
+	require.Equal(t, certificate.Provider, salted.Provider)
+	require.NotEqual(t, certificate.LeafHash, salted.LeafHash)
+	require.Equal(t, zkcertificate.DID(salted.Standard, salted.LeafHash), salted.DID)
+
+	expectedLeafHash, err := zkcertificate.LeafHash(
+		salted.ContentHash,
+		&salted.Provider.PublicKey,
+		&salted.Provider.Signature,
+		salted.HolderCommitment,
+		2,
+		time.Time(salted.ExpirationDate),
+	)
+	require.NoError(t, err)
+	require.Equal(t, expectedLeafHash, salted.LeafHash)
+
+	_, err = certificate.WithSalt(certificate.RandomSalt)
+	require.Error(t, err)
+}
+
+func TestVerifyChain(t *testing.T) {
+	first, _ := makeCertificate(t)
+	second, _ := makeCertificate(t)
+	third, _ := makeCertificate(t)
+
+	second, err := second.LinkToPrevious(first.DID)
+	require.NoError(t, err)
+	third, err = third.LinkToPrevious(second.DID)
+	require.NoError(t, err)
+
+	chain := []*zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{first, second, third}
+	require.NoError(t, zkcertificate.VerifyChain(chain))
+
+	require.NoError(t, zkcertificate.VerifyChain(chain[1:]))
+	require.Error(t, zkcertificate.VerifyChain([]*zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{third, second}))
+	require.Error(t, zkcertificate.VerifyChain([]*zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{first, third}))
+
+	third.LinkedPreviousDID = first.DID
+	require.Error(t, zkcertificate.VerifyChain(chain), "tampered link must be detected")
+}
+
+func TestProviderData_Compact(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	encoded, err := certificate.Provider.MarshalCompact()
+	require.NoError(t, err)
+	require.Len(t, encoded, 214)
+
+	var decoded zkcertificate.ProviderData
+	require.NoError(t, decoded.UnmarshalCompact(encoded))
+	require.Equal(t, certificate.Provider, decoded)
+}
+
+func TestProviderData_UnmarshalCompact_invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "Invalid base64", input: "not base64!"},
+		{name: "Invalid length", input: "AAAA"},
+		{name: "Not on curve", input: strings.Repeat("A", 214)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decoded zkcertificate.ProviderData
+			require.Error(t, decoded.UnmarshalCompact(tt.input))
+		})
+	}
+}
+
+func TestCertificate_ProviderPublicKeyHash(t *testing.T) {
+	certificate, privateKey := makeCertificate(t)
+
+	hash, err := certificate.ProviderPublicKeyHash()
+	require.NoError(t, err)
+
+	expected, err := poseidon.Hash([]*big.Int{privateKey.Public().X, privateKey.Public().Y})
+	require.NoError(t, err)
+	require.Equal(t, zkcertificate.HashFromBigInt(expected), hash)
+
+	_, err = (&zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{}).ProviderPublicKeyHash()
+	require.Error(t, err)
+}
+
+func TestCertificate_HashWithNonce(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	nullifier, err := certificate.HashWithNonce(big.NewInt(11))
+	require.NoError(t, err)
+
+	expected, err := poseidon.Hash([]*big.Int{certificate.LeafHash.BigInt(), big.NewInt(11)})
+	require.NoError(t, err)
+	require.Equal(t, zkcertificate.HashFromBigInt(expected), nullifier)
+
+	_, err = certificate.HashWithNonce(nil)
+	require.Error(t, err)
+
+	_, err = certificate.HashWithNonce(new(big.Int).Neg(big.NewInt(1)))
+	require.Error(t, err)
+}
+
+func TestCertificate_Fingerprint(t *testing.T) {
+	certificate, privateKey := makeCertificate(t)
+
+	fingerprint, err := certificate.Fingerprint()
+	require.NoError(t, err)
+
+	renewed, err := zkcertificate.New(
+		certificate.HolderCommitment,
+		certificate.Content,
+		privateKey.Public(),
+		&certificate.Provider.Signature,
+		2,
+		time.Unix(1800000000, 0),
+	)
+	require.NoError(t, err)
+	require.NotEqual(t, certificate.LeafHash, renewed.LeafHash)
+
+	renewedFingerprint, err := renewed.Fingerprint()
+	require.NoError(t, err)
+	require.Equal(t, fingerprint, renewedFingerprint)
+
+	newContent, err := zkcertificate.SimpleJSON{"name": "John Doe", "age": "31"}.FFEncode()
+	require.NoError(t, err)
+
+	updated, err := certificate.WithContent(newContent, privateKey, 1)
+	require.NoError(t, err)
+
+	updatedFingerprint, err := updated.Fingerprint()
+	require.NoError(t, err)
+	require.NotEqual(t, fingerprint, updatedFingerprint)
+
+	_, err = (&zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{}).Fingerprint()
+	require.Error(t, err)
+}
+
+func makeCertificate(t *testing.T) (*zkcertificate.Certificate[zkcertificate.SimpleJSONContent], babyjub.PrivateKey) {
+	t.Helper()
+
+	privateKey := babyjub.NewRandPrivKey()
+	holderCommitment := zkcertificate.HashFromBigInt(big.NewInt(7))
+
+	content, err := zkcertificate.SimpleJSON{"name": "John Doe", "age": "30"}.FFEncode()
+	require.NoError(t, err)
+
+	contentHash, err := content.Hash()
+	require.NoError(t, err)
+
+	signature, err := zkcertificate.SignCertificate(privateKey, contentHash, holderCommitment)
+	require.NoError(t, err)
+
+	certificate, err := zkcertificate.New(
+		holderCommitment,
+		content,
+		privateKey.Public(),
+		signature,
+		1,
+		time.Unix(1700000000, 0),
+	)
+	require.NoError(t, err)
+
+	return certificate, privateKey
+}
+
+func TestNew_standardVersion(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+	require.Zero(t, certificate.StandardVersion)
+	require.Zero(t, certificate.Clone().StandardVersion)
+}
+
+func TestNew_invalidHolderCommitment(t *testing.T) {
+	certificate, privateKey := makeCertificate(t)
+
+	_, err := zkcertificate.New(
+		zkcertificate.HashFromBigInt(constants.Q),
+		certificate.Content,
+		privateKey.Public(),
+		&certificate.Provider.Signature,
+		1,
+		time.Unix(1700000000, 0),
+	)
+	require.Error(t, err)
+}
+
+func TestNew_generatedHolderCommitment(t *testing.T) {
+	privateKey := babyjub.NewRandPrivKey()
+
+	content, err := zkcertificate.SimpleJSON{"name": "John Doe"}.FFEncode()
+	require.NoError(t, err)
+
+	contentHash, err := content.Hash()
+	require.NoError(t, err)
+
+	// Poseidon outputs range over the whole field, so most commitments exceed the subgroup order.
+	for i := 0; i < 20; i++ {
+		holderSecret, err := zkcertificate.GenerateHolderSecret()
+		require.NoError(t, err)
+
+		holderCommitment, err := zkcertificate.GenerateHolderCommitment(holderSecret)
+		require.NoError(t, err)
+
+		signature, err := zkcertificate.SignCertificate(privateKey, contentHash, holderCommitment)
+		require.NoError(t, err)
+
+		_, err = zkcertificate.New(holderCommitment, content, privateKey.Public(), signature, 1, time.Unix(1700000000, 0))
+		require.NoError(t, err)
+	}
+}
+
+func TestNew_contentValidator(t *testing.T) {
+	errBornInFuture := errors.New("born in the future")
+	bornInPast := zkcertificate.ContentValidatorFunc[zkcertificate.KYCContent](func(content zkcertificate.KYCContent) error {
+		if int(content.YearOfBirth) > time.Now().Year() {
+			return errBornInFuture
+		}
+
+		return nil
+	})
+
+	tests := []struct {
+		name        string
+		yearOfBirth uint16
+		wantErr     bool
+	}{
+		{name: "valid", yearOfBirth: 1989},
+		{name: "invalid", yearOfBirth: 3000, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privateKey := babyjub.NewRandPrivKey()
+			holderCommitment := zkcertificate.HashFromBigInt(big.NewInt(7))
+
+			content, err := zkcertificate.KYCInputs{
+				Surname:      "Doe",
+				Forename:     "John",
+				YearOfBirth:  tt.yearOfBirth,
+				MonthOfBirth: 5,
+				DayOfBirth:   28,
+				Citizenship:  "LIE",
+				Country:      "LIE",
+			}.FFEncode()
+			require.NoError(t, err)
+
+			contentHash, err := content.Hash()
+			require.NoError(t, err)
+
+			signature, err := zkcertificate.SignCertificate(privateKey, contentHash, holderCommitment)
+			require.NoError(t, err)
+
+			_, err = zkcertificate.New(
+				holderCommitment,
+				content,
+				privateKey.Public(),
+				signature,
+				1,
+				time.Unix(1700000000, 0),
+				zkcertificate.WithContentValidator[zkcertificate.KYCContent](bornInPast),
+			)
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, errBornInFuture)
+
+			var certificateError *zkcertificate.CertificateError
+			require.ErrorAs(t, err, &certificateError)
+			require.Equal(t, zkcertificate.InvalidContent, certificateError.Kind)
+		})
+	}
+}
+
+func TestRegistrationDetails_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		details zkcertificate.RegistrationDetails
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			details: zkcertificate.RegistrationDetails{
+				Address:   common.HexToAddress("0x8eD8311ee2F3de2b3e8C6E0c7A5bA1d11DB28aB1"),
+				LeafIndex: 1,
+			},
+		},
+		{
+			name:    "zero address",
+			details: zkcertificate.RegistrationDetails{LeafIndex: 1},
+			wantErr: true,
+		},
+		{
+			name: "negative leaf index",
+			details: zkcertificate.RegistrationDetails{
+				Address:   common.HexToAddress("0x8eD8311ee2F3de2b3e8C6E0c7A5bA1d11DB28aB1"),
+				LeafIndex: -1,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.details.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIssuedCertificate_ValidateRegistration(t *testing.T) {
+	issued := makeIssuedCertificate(t)
+	require.Error(t, issued.ValidateRegistration())
+
+	issued.Registration.Address = common.HexToAddress("0x8eD8311ee2F3de2b3e8C6E0c7A5bA1d11DB28aB1")
+	require.NoError(t, issued.ValidateRegistration())
+
+	issued.MerkleProof.LeafIndex = 2
+	require.Error(t, issued.ValidateRegistration())
+}
+
+func TestIssuedCertificate_UpdateMerkleProof(t *testing.T) {
+	issued := makeIssuedCertificate(t)
+
+	tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+	require.NoError(t, tree.SetLeaf(1, merkle.TreeNode{Value: uint256.MustFromBig(issued.LeafHash.BigInt())}))
+	require.NoError(t, tree.SetLeaf(0, merkle.TreeNode{Value: uint256.NewInt(42)}))
+
+	require.NoError(t, issued.UpdateMerkleProof(tree))
+
+	expectedProof, err := tree.GetProof(1)
+	require.NoError(t, err)
+	require.Equal(t, expectedProof, issued.MerkleProof)
+
+	require.NoError(t, tree.SetLeaf(1, merkle.TreeNode{Value: uint256.NewInt(43)}))
+	require.Error(t, issued.UpdateMerkleProof(tree))
+	require.Equal(t, expectedProof, issued.MerkleProof)
+}
+
+func TestIssuedCertificate_UpdateMerkleProof_hashFunc(t *testing.T) {
+	issued := makeIssuedCertificate(t)
+
+	sum := func(input []*big.Int) (*big.Int, error) {
+		res := new(big.Int)
+		for _, v := range input {
+			res.Add(res, v)
+		}
+
+		return res, nil
+	}
+
+	tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue, merkle.WithHashFunc(sum))
+	require.NoError(t, err)
+	require.NoError(t, tree.SetLeaf(1, merkle.TreeNode{Value: uint256.MustFromBig(issued.LeafHash.BigInt())}))
+
+	require.NoError(t, issued.UpdateMerkleProof(tree))
+
+	expectedProof, err := tree.GetProof(1)
+	require.NoError(t, err)
+	require.Equal(t, expectedProof, issued.MerkleProof)
+}
+
+func TestIssuedCertificate_UpdateExpiration(t *testing.T) {
+	issued, providerKey := makeIssuedCertificateWithKey(t)
+	newExpiration := time.Unix(1800000000, 0)
+
+	tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+	require.NoError(t, tree.SetLeaf(1, merkle.TreeNode{Value: uint256.MustFromBig(issued.LeafHash.BigInt())}))
+	require.NoError(t, tree.SetLeaf(0, merkle.TreeNode{Value: uint256.NewInt(42)}))
+
+	previous := issued.Certificate.Clone()
+
+	require.NoError(t, issued.UpdateExpiration(tree, providerKey, newExpiration))
+	require.Equal(t, newExpiration.Unix(), time.Time(issued.ExpirationDate).Unix())
+	require.NotEqual(t, previous.LeafHash, issued.LeafHash)
+	require.NotEqual(t, previous.DID, issued.DID)
+	require.Equal(t, previous.ContentHash, issued.ContentHash)
+
+	valid, err := zkcertificate.VerifySignature(
+		providerKey.Public(),
+		issued.ContentHash,
+		issued.HolderCommitment,
+		&issued.Provider.Signature,
+	)
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	leaf, err := tree.GetLeaf(1)
+	require.NoError(t, err)
+	require.Equal(t, issued.LeafHash.BigInt(), leaf.Value.ToBig())
+
+	expectedProof, err := tree.GetProof(1)
+	require.NoError(t, err)
+	require.Equal(t, expectedProof, issued.MerkleProof)
+}
+
+func TestIssuedCertificate_UpdateExpiration_invalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		leafValue     uint64
+		foreignKey    bool
+		newExpiration time.Time
+	}{
+		{name: "expiration not extended", newExpiration: time.Unix(1600000000, 0)},
+		{name: "leaf mismatch", leafValue: 43, newExpiration: time.Unix(1800000000, 0)},
+		{name: "foreign provider key", foreignKey: true, newExpiration: time.Unix(1800000000, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issued, providerKey := makeIssuedCertificateWithKey(t)
+			if tt.foreignKey {
+				providerKey = babyjub.NewRandPrivKey()
+			}
+
+			leafValue := uint256.MustFromBig(issued.LeafHash.BigInt())
+			if tt.leafValue != 0 {
+				leafValue = uint256.NewInt(tt.leafValue)
+			}
+
+			tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue)
+			require.NoError(t, err)
+			require.NoError(t, tree.SetLeaf(1, merkle.TreeNode{Value: leafValue}))
+
+			root := tree.Root()
+			expected := *issued
+
+			require.Error(t, issued.UpdateExpiration(tree, providerKey, tt.newExpiration))
+			require.Equal(t, expected, *issued)
+			require.Equal(t, root, tree.Root())
+		})
+	}
+}
+
+func TestCertificate_ExpirationWarning(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+	expirationDate := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		warnBefore time.Duration
+		want       bool
+	}{
+		{name: "long before expiration", now: expirationDate.Add(-48 * time.Hour), warnBefore: 24 * time.Hour, want: false},
+		{name: "within warning period", now: expirationDate.Add(-12 * time.Hour), warnBefore: 24 * time.Hour, want: true},
+		{name: "warning period start", now: expirationDate.Add(-24 * time.Hour), warnBefore: 24 * time.Hour, want: true},
+		{name: "at expiration", now: expirationDate, warnBefore: 24 * time.Hour, want: true},
+		{name: "expired", now: expirationDate.Add(time.Hour), warnBefore: 24 * time.Hour, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, certificate.ExpirationWarning(tt.now, tt.warnBefore))
+		})
+	}
+}
+
+func TestCertificate_AgeAtExpiry(t *testing.T) {
+	tests := []struct {
+		name           string
+		expirationDate time.Time
+		dateOfBirth    time.Time
+		want           int
+		wantErr        bool
+	}{
+		{
+			name:           "birthday passed",
+			expirationDate: time.Date(2024, time.June, 15, 0, 0, 0, 0, time.UTC),
+			dateOfBirth:    time.Date(2000, time.June, 14, 0, 0, 0, 0, time.UTC),
+			want:           24,
+		},
+		{
+			name:           "on birthday",
+			expirationDate: time.Date(2024, time.June, 15, 0, 0, 0, 0, time.UTC),
+			dateOfBirth:    time.Date(2000, time.June, 15, 0, 0, 0, 0, time.UTC),
+			want:           24,
+		},
+		{
+			name:           "day before birthday",
+			expirationDate: time.Date(2024, time.June, 15, 0, 0, 0, 0, time.UTC),
+			dateOfBirth:    time.Date(2000, time.June, 16, 0, 0, 0, 0, time.UTC),
+			want:           23,
+		},
+		{
+			name:           "leap day birthday in non-leap year",
+			expirationDate: time.Date(2023, time.February, 28, 0, 0, 0, 0, time.UTC),
+			dateOfBirth:    time.Date(2004, time.February, 29, 0, 0, 0, 0, time.UTC),
+			want:           18,
+		},
+		{
+			name:           "leap day birthday after february",
+			expirationDate: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+			dateOfBirth:    time.Date(2004, time.February, 29, 0, 0, 0, 0, time.UTC),
+			want:           19,
+		},
+		{
+			name:           "born after expiration",
+			expirationDate: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+			dateOfBirth:    time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			wantErr:        true,
+		},
+		{
+			name:        "no expiration date",
+			dateOfBirth: time.Date(2000, time.June, 14, 0, 0, 0, 0, time.UTC),
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certificate := zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{
+				ExpirationDate: zkcertificate.Timestamp(tt.expirationDate),
+			}
+
+			age, err := certificate.AgeAtExpiry(tt.dateOfBirth)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, age)
+		})
+	}
+}
+
+func TestProviderData_Equal(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+	provider := certificate.Provider
+
+	data, err := json.Marshal(provider)
+	require.NoError(t, err)
+
+	var decoded zkcertificate.ProviderData
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.True(t, provider.Equal(decoded))
+
+	decoded.Signature.S = new(big.Int).Add(decoded.Signature.S, big.NewInt(1))
+	require.False(t, provider.Equal(decoded))
+
+	other, _ := makeCertificate(t)
+	require.False(t, provider.Equal(other.Provider))
+
+	require.True(t, zkcertificate.ProviderData{}.Equal(zkcertificate.ProviderData{}))
+	require.False(t, provider.Equal(zkcertificate.ProviderData{}))
+}
+
+func TestCertificate_Clone(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	originalX := new(big.Int).Set(certificate.Provider.PublicKey.X)
+	originalContent := certificate.Content.Clone()
+
+	clone := certificate.Clone()
+	require.True(t, certificate.Provider.Equal(clone.Provider))
+	require.Equal(t, certificate.DID, clone.DID)
+	require.Equal(t, certificate.LeafHash, clone.LeafHash)
+	require.Equal(t, certificate.Content, clone.Content)
+
+	clone.Provider.PublicKey.X.Add(clone.Provider.PublicKey.X, big.NewInt(1))
+	clone.Provider.Signature.R8.Y.SetInt64(1)
+	clone.Content[0] = zkcertificate.HashFromBigInt(big.NewInt(1))
+
+	require.Equal(t, originalX, certificate.Provider.PublicKey.X)
+	require.NotEqual(t, big.NewInt(1), certificate.Provider.Signature.R8.Y)
+	require.Equal(t, originalContent, certificate.Content)
+}
+
+func TestCertificate_SaltedContentHash(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	saltedContentHash, err := certificate.SaltedContentHash()
+	require.NoError(t, err)
+
+	expected, err := poseidon.Hash([]*big.Int{certificate.ContentHash.BigInt(), big.NewInt(certificate.RandomSalt)})
+	require.NoError(t, err)
+	require.Equal(t, zkcertificate.HashFromBigInt(expected), saltedContentHash)
+
+	leafHash, err := zkcertificate.LeafHash(
+		certificate.ContentHash,
+		&certificate.Provider.PublicKey,
+		&certificate.Provider.Signature,
+		certificate.HolderCommitment,
+		certificate.RandomSalt,
+		time.Time(certificate.ExpirationDate),
+		zkcertificate.WithSaltedContent(),
+	)
+	require.NoError(t, err)
+	require.NotEqual(t, certificate.LeafHash, leafHash)
+
+	expectedLeafHash, err := zkcertificate.LeafHash(
+		saltedContentHash,
+		&certificate.Provider.PublicKey,
+		&certificate.Provider.Signature,
+		certificate.HolderCommitment,
+		certificate.RandomSalt,
+		time.Time(certificate.ExpirationDate),
+	)
+	require.NoError(t, err)
+	require.Equal(t, expectedLeafHash, leafHash)
+}
+
+func TestCertificate_WithSaltedContent(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	salted, err := certificate.WithSaltedContent()
+	require.NoError(t, err)
+	require.True(t, salted.SaltedContent)
+	require.False(t, certificate.SaltedContent)
+
+	expected, err := zkcertificate.LeafHash(
+		certificate.ContentHash,
+		&certificate.Provider.PublicKey,
+		&certificate.Provider.Signature,
+		certificate.HolderCommitment,
+		certificate.RandomSalt,
+		time.Time(certificate.ExpirationDate),
+		zkcertificate.WithSaltedContent(),
+	)
+	require.NoError(t, err)
+	require.Equal(t, expected, salted.LeafHash)
+	require.NoError(t, zkcertificate.VerifyChain([]*zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{salted}))
+
+	_, err = salted.WithSaltedContent()
+	require.Error(t, err)
+
+	resalted, err := salted.WithSalt(2)
+	require.NoError(t, err)
+	require.True(t, resalted.SaltedContent)
+	require.NoError(t, zkcertificate.VerifyChain([]*zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{resalted}))
+
+	data, err := json.Marshal(salted)
+	require.NoError(t, err)
+
+	var decoded zkcertificate.Certificate[zkcertificate.SimpleJSONContent]
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.True(t, decoded.SaltedContent)
+	require.NoError(t, zkcertificate.VerifyChain([]*zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{&decoded}))
+
+	committed, err := salted.WithFieldsCommitment()
+	require.NoError(t, err)
+
+	redacted, err := committed.Redact([]string{"1"})
+	require.NoError(t, err)
+	require.NoError(t, redacted.Verify())
+}
+
+func TestIssuedCertificate_ToOnChainRepresentation(t *testing.T) {
+	issued := makeIssuedCertificate(t)
+	issued.Registration.Address = common.HexToAddress("0x8eD8311ee2F3de2b3e8C6E0c7A5bA1d11DB28aB1")
+
+	leafHash, registryAddress, leafIndex := issued.ToOnChainRepresentation()
+	require.Equal(t, issued.LeafHash.BigInt(), new(big.Int).SetBytes(leafHash[:]))
+	require.Equal(t, issued.Registration.Address, registryAddress)
+	require.Equal(t, big.NewInt(1), leafIndex)
+}
+
+// TestLeafHashVector pins LeafHash to a fixed test vector, so it can be cross-validated against
+// circuit implementations. The inputs were produced with the private key 0x0102...20 signing
+// Poseidon(contentHash, holderCommitment). The test must fail if the leaf hash function changes.
+func TestLeafHashVector(t *testing.T) {
+	providerPublicKey := &babyjub.PublicKey{
+		X: mustBigIntFromHex(t, "0x01fb27be1c28984de1ff3e0592ee7454fa17d5f20561be3a97b6bc48c2b7e7e2"),
+		Y: mustBigIntFromHex(t, "0x2279cb2bb680d8d118d742fa669150845659c76b90b7c3d7f8a470976beed219"),
+	}
+	signature := &babyjub.Signature{
+		R8: &babyjub.Point{
+			X: mustBigIntFromHex(t, "0x0ffc2554d78fde7c4817d864007a8623dc0d540d93f3b24d78bd6d8b468dd9d4"),
+			Y: mustBigIntFromHex(t, "0x03c572524b9faa8c4a1122c2929f1d19edb0a1eb76f62a36a2837d2a8c562c62"),
+		},
+		S: mustHashFromString("1565341089686587294725610925521320389600383870169153768906645830163454629906").BigInt(),
+	}
+	contentHash := zkcertificate.HashFromBigInt(big.NewInt(123456789))
+	holderCommitment := zkcertificate.HashFromBigInt(big.NewInt(11))
+
+	isValid, err := zkcertificate.VerifySignature(providerPublicKey, contentHash, holderCommitment, signature)
+	require.NoError(t, err)
+	require.True(t, isValid)
+
+	leafHash, err := zkcertificate.LeafHash(
+		contentHash,
+		providerPublicKey,
+		signature,
+		holderCommitment,
+		42,
+		time.Unix(1735689600, 0),
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		"11027176617615252435199092142603338755261049293264703929044689281807810715917",
+		leafHash.String(),
+	)
+}
+
+func mustBigIntFromHex(t *testing.T, s string) *big.Int {
+	t.Helper()
+
+	v, ok := new(big.Int).SetString(strings.TrimPrefix(s, "0x"), 16)
+	require.True(t, ok, "invalid hex big int %q", s)
+
+	return v
+}
+
+func TestProviderDataToEthereumAddress(t *testing.T) {
+	// Public key of the private key 0x0102...20, see TestLeafHashVector.
+	providerData := zkcertificate.ProviderData{
+		PublicKey: babyjub.PublicKey{
+			X: mustBigIntFromHex(t, "0x01fb27be1c28984de1ff3e0592ee7454fa17d5f20561be3a97b6bc48c2b7e7e2"),
+			Y: mustBigIntFromHex(t, "0x2279cb2bb680d8d118d742fa669150845659c76b90b7c3d7f8a470976beed219"),
+		},
+	}
+
+	address, err := providerData.ToEthereumAddress()
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("0x7787FCE5Bc604ff85D20C11F5B08F45d277a953b"), address)
+
+	_, err = zkcertificate.ProviderData{}.ToEthereumAddress()
+	require.Error(t, err)
+}
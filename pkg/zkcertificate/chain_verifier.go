@@ -0,0 +1,88 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"fmt"
+	"time"
+)
+
+// CertificateChainVerifier validates a chain of linked certificates, e.g. an issuance followed by renewals.
+type CertificateChainVerifier[T any] struct {
+	// JustifyAmendment is called for consecutive certificates with different content hashes.
+	// The content change is accepted if it returns nil. If JustifyAmendment is not set,
+	// every content change is rejected, so only pure renewals are accepted.
+	JustifyAmendment func(previous, next *Certificate[T]) error
+}
+
+// Verify checks the chain ordered from the oldest to the newest certificate. It verifies that
+//   - the first certificate has no predecessor;
+//   - leaf hashes and DIDs are valid and every certificate is linked to the DID of its predecessor;
+//   - provider signatures are valid;
+//   - expiration dates are non-decreasing;
+//   - content hashes are identical, unless the amendment is justified by JustifyAmendment.
+func (v CertificateChainVerifier[T]) Verify(chain []*Certificate[T]) error {
+	if len(chain) == 0 {
+		return fmt.Errorf("empty certificate chain")
+	}
+
+	if chain[0].LinkedPreviousDID != "" {
+		return fmt.Errorf("first certificate is linked to %q", chain[0].LinkedPreviousDID)
+	}
+
+	if err := VerifyChain(chain); err != nil {
+		return err
+	}
+
+	for i, certificate := range chain {
+		signatureValid, err := VerifySignature(
+			&certificate.Provider.PublicKey,
+			certificate.ContentHash,
+			certificate.HolderCommitment,
+			&certificate.Provider.Signature,
+		)
+		if err != nil {
+			return fmt.Errorf("verify signature of certificate %d: %w", i, err)
+		}
+		if !signatureValid {
+			return fmt.Errorf("certificate %d has invalid signature", i)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		previous := chain[i-1]
+
+		if time.Time(certificate.ExpirationDate).Before(time.Time(previous.ExpirationDate)) {
+			return fmt.Errorf("certificate %d expires before certificate %d", i, i-1)
+		}
+
+		if certificate.ContentHash.Cmp(previous.ContentHash) == 0 {
+			continue
+		}
+
+		if v.JustifyAmendment == nil {
+			return fmt.Errorf("certificate %d changes content of certificate %d", i, i-1)
+		}
+
+		if err := v.JustifyAmendment(previous, certificate); err != nil {
+			return fmt.Errorf("certificate %d has unjustified content amendment: %w", i, err)
+		}
+	}
+
+	return nil
+}
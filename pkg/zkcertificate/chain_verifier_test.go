@@ -0,0 +1,104 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestCertificateChainVerifier_Verify(t *testing.T) {
+	first, privateKey := makeCertificate(t)
+	second := renewCertificate(t, first, privateKey, time.Unix(1800000000, 0))
+	third := renewCertificate(t, second, privateKey, time.Unix(1900000000, 0))
+
+	verifier := zkcertificate.CertificateChainVerifier[zkcertificate.SimpleJSONContent]{}
+	require.NoError(t, verifier.Verify([]*zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{first, second, third}))
+
+	require.Error(t, verifier.Verify(nil))
+	require.Error(t, verifier.Verify([]*zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{second, third}),
+		"chain must start with a certificate without predecessor")
+
+	shortened := renewCertificate(t, first, privateKey, time.Unix(1600000000, 0))
+	require.Error(t, verifier.Verify([]*zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{first, shortened}),
+		"expiration dates must be non-decreasing")
+
+	forged := renewCertificate(t, first, privateKey, time.Unix(1800000000, 0))
+	forged.Provider.Signature.S = big.NewInt(1)
+	require.Error(t, verifier.Verify([]*zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{first, forged}))
+}
+
+func TestCertificateChainVerifier_Verify_amendment(t *testing.T) {
+	first, privateKey := makeCertificate(t)
+
+	newContent, err := zkcertificate.SimpleJSON{"name": "John Doe", "age": "31"}.FFEncode()
+	require.NoError(t, err)
+
+	amended, err := first.WithContent(newContent, privateKey, 2)
+	require.NoError(t, err)
+
+	amended, err = amended.LinkToPrevious(first.DID)
+	require.NoError(t, err)
+
+	chain := []*zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{first, amended}
+
+	verifier := zkcertificate.CertificateChainVerifier[zkcertificate.SimpleJSONContent]{}
+	require.Error(t, verifier.Verify(chain))
+
+	verifier.JustifyAmendment = func(previous, next *zkcertificate.Certificate[zkcertificate.SimpleJSONContent]) error {
+		return nil
+	}
+	require.NoError(t, verifier.Verify(chain))
+
+	verifier.JustifyAmendment = func(previous, next *zkcertificate.Certificate[zkcertificate.SimpleJSONContent]) error {
+		return errors.New("no supporting document")
+	}
+	require.Error(t, verifier.Verify(chain))
+}
+
+func renewCertificate(
+	t *testing.T,
+	previous *zkcertificate.Certificate[zkcertificate.SimpleJSONContent],
+	privateKey babyjub.PrivateKey,
+	expirationDate time.Time,
+) *zkcertificate.Certificate[zkcertificate.SimpleJSONContent] {
+	t.Helper()
+
+	signature, err := zkcertificate.SignCertificate(privateKey, previous.ContentHash, previous.HolderCommitment)
+	require.NoError(t, err)
+
+	renewed, err := zkcertificate.New(
+		previous.HolderCommitment,
+		previous.Content,
+		privateKey.Public(),
+		signature,
+		previous.RandomSalt+1,
+		expirationDate,
+	)
+	require.NoError(t, err)
+
+	renewed, err = renewed.LinkToPrevious(previous.DID)
+	require.NoError(t, err)
+
+	return renewed
+}
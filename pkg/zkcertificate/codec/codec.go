@@ -0,0 +1,223 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+// Package codec encodes zero-knowledge certificates in binary and text formats other than JSON.
+package codec
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+// Supported certificate encoding formats.
+const (
+	FormatJSON    = "json"
+	FormatCBOR    = "cbor"
+	FormatMsgPack = "msgpack"
+	FormatHex     = "hex"
+)
+
+// ErrUnsupportedFormat is returned when a certificate is encoded or decoded with an unknown format.
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// bigIntExtID is the MessagePack extension type of integers that do not fit into 64 bits.
+const bigIntExtID = 1
+
+func init() {
+	msgpack.RegisterExt(bigIntExtID, (*bigInt)(nil))
+}
+
+var cborDecMode = mustCBORDecMode()
+
+func mustCBORDecMode() cbor.DecMode {
+	mode, err := cbor.DecOptions{
+		DefaultMapType: reflect.TypeOf(map[string]interface{}(nil)),
+		BigIntDec:      cbor.BigIntDecodePointer,
+	}.DecMode()
+	if err != nil {
+		panic(err)
+	}
+
+	return mode
+}
+
+// Encode encodes the certificate in the given format.
+// CBOR and MessagePack encode the same document structure as JSON, so all formats are interchangeable.
+// Integers that do not fit into 64 bits are encoded as CBOR bignums and as a MessagePack extension,
+// so they are decoded exactly. Hex is the hex encoded JSON representation.
+// An error wrapping ErrUnsupportedFormat is returned for unknown formats.
+func Encode[T any](c *zkcertificate.Certificate[T], format string) ([]byte, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("encode certificate to json: %w", err)
+	}
+
+	switch format {
+	case FormatJSON:
+		return data, nil
+	case FormatHex:
+		return []byte(hex.EncodeToString(data)), nil
+	case FormatCBOR, FormatMsgPack:
+		document, err := decodeJSONDocument(data, format)
+		if err != nil {
+			return nil, err
+		}
+
+		if format == FormatCBOR {
+			return cbor.Marshal(document)
+		}
+
+		return msgpack.Marshal(document)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// Decode decodes a certificate encoded with Encode in the given format.
+// An error wrapping ErrUnsupportedFormat is returned for unknown formats.
+func Decode[T zkcertificate.Content](data []byte, format string) (*zkcertificate.Certificate[T], error) {
+	var jsonData []byte
+
+	switch format {
+	case FormatJSON:
+		jsonData = data
+	case FormatHex:
+		decoded, err := hex.DecodeString(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("decode hex: %w", err)
+		}
+
+		jsonData = decoded
+	case FormatCBOR, FormatMsgPack:
+		var document interface{}
+
+		var err error
+		if format == FormatCBOR {
+			err = cborDecMode.Unmarshal(data, &document)
+		} else {
+			err = msgpack.Unmarshal(data, &document)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decode %s: %w", format, err)
+		}
+
+		jsonData, err = json.Marshal(document)
+		if err != nil {
+			return nil, fmt.Errorf("encode %s document to json: %w", format, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+
+	var certificate zkcertificate.Certificate[T]
+	if err := json.Unmarshal(jsonData, &certificate); err != nil {
+		return nil, fmt.Errorf("decode certificate from json: %w", err)
+	}
+
+	return &certificate, nil
+}
+
+// decodeJSONDocument decodes JSON into generic values for the given format, keeping integers exact.
+func decodeJSONDocument(data []byte, format string) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("decode json document: %w", err)
+	}
+
+	return convertJSONNumbers(document, format)
+}
+
+func convertJSONNumbers(value interface{}, format string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			converted, err := convertJSONNumbers(item, format)
+			if err != nil {
+				return nil, err
+			}
+
+			v[key] = converted
+		}
+	case []interface{}:
+		for i, item := range v {
+			converted, err := convertJSONNumbers(item, format)
+			if err != nil {
+				return nil, err
+			}
+
+			v[i] = converted
+		}
+	case json.Number:
+		if strings.ContainsAny(v.String(), ".eE") {
+			n, err := v.Float64()
+			if err != nil {
+				return nil, fmt.Errorf("decode json number %s: %w", v, err)
+			}
+
+			return n, nil
+		}
+
+		n, ok := new(big.Int).SetString(v.String(), 10)
+		if !ok {
+			return nil, fmt.Errorf("decode json number %s", v)
+		}
+
+		switch {
+		case n.IsInt64():
+			return n.Int64(), nil
+		case n.IsUint64():
+			return n.Uint64(), nil
+		case format == FormatMsgPack:
+			return (*bigInt)(n), nil
+		default:
+			return n, nil
+		}
+	}
+
+	return value, nil
+}
+
+// bigInt is an integer that does not fit into 64 bits. MessagePack has no native type for such integers,
+// so they are encoded as an extension holding the decimal representation.
+type bigInt big.Int
+
+// MarshalMsgpack implements [msgpack.Marshaler].
+func (b *bigInt) MarshalMsgpack() ([]byte, error) {
+	return (*big.Int)(b).MarshalText()
+}
+
+// UnmarshalMsgpack implements [msgpack.Unmarshaler].
+func (b *bigInt) UnmarshalMsgpack(data []byte) error {
+	return (*big.Int)(b).UnmarshalText(data)
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (b *bigInt) MarshalJSON() ([]byte, error) {
+	return (*big.Int)(b).MarshalJSON()
+}
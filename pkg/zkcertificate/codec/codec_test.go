@@ -0,0 +1,131 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package codec_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate/codec"
+)
+
+func TestEncode(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+	certificate.RandomSalt = 1<<62 + 1
+
+	tests := []struct {
+		name   string
+		format string
+	}{
+		{name: "json", format: codec.FormatJSON},
+		{name: "cbor", format: codec.FormatCBOR},
+		{name: "msgpack", format: codec.FormatMsgPack},
+		{name: "hex", format: codec.FormatHex},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := codec.Encode(certificate, tt.format)
+			require.NoError(t, err)
+
+			decoded, err := codec.Decode[zkcertificate.SimpleJSONContent](data, tt.format)
+			require.NoError(t, err)
+			require.Equal(t, certificate.DID, decoded.DID)
+			require.Equal(t, certificate.LeafHash, decoded.LeafHash)
+			require.Equal(t, certificate.Content, decoded.Content)
+			require.Equal(t, certificate.RandomSalt, decoded.RandomSalt)
+			require.True(t, certificate.Provider.Equal(decoded.Provider))
+		})
+	}
+}
+
+func TestEncode_unsupportedFormat(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	_, err := codec.Encode(certificate, "xml")
+	require.ErrorIs(t, err, codec.ErrUnsupportedFormat)
+
+	_, err = codec.Decode[zkcertificate.SimpleJSONContent](nil, "xml")
+	require.ErrorIs(t, err, codec.ErrUnsupportedFormat)
+}
+
+func TestEncode_bigInteger(t *testing.T) {
+	value, ok := new(big.Int).SetString("-340282366920938463463374607431768211457", 10)
+	require.True(t, ok)
+
+	privateKey := babyjub.NewRandPrivKey()
+	holderCommitment := zkcertificate.HashFromBigInt(big.NewInt(7))
+	content := numberContent{Value: value}
+
+	contentHash, err := content.Hash()
+	require.NoError(t, err)
+
+	signature, err := zkcertificate.SignCertificate(privateKey, contentHash, holderCommitment)
+	require.NoError(t, err)
+
+	certificate, err := zkcertificate.New(holderCommitment, content, privateKey.Public(), signature, 1, time.Unix(1700000000, 0))
+	require.NoError(t, err)
+
+	for _, format := range []string{codec.FormatCBOR, codec.FormatMsgPack} {
+		t.Run(format, func(t *testing.T) {
+			data, err := codec.Encode(certificate, format)
+			require.NoError(t, err)
+
+			decoded, err := codec.Decode[numberContent](data, format)
+			require.NoError(t, err)
+			require.Zero(t, value.Cmp(decoded.Content.Value))
+		})
+	}
+}
+
+// numberContent is certificate content with an integer that does not fit into 64 bits.
+type numberContent struct {
+	Value *big.Int `json:"value"`
+}
+
+func (c numberContent) Hash() (zkcertificate.Hash, error) {
+	return zkcertificate.HashFromBigInt(big.NewInt(1)), nil
+}
+
+func (c numberContent) Standard() zkcertificate.Standard {
+	return zkcertificate.StandardSimpleJSON
+}
+
+func makeCertificate(t *testing.T) (*zkcertificate.Certificate[zkcertificate.SimpleJSONContent], babyjub.PrivateKey) {
+	t.Helper()
+
+	privateKey := babyjub.NewRandPrivKey()
+	holderCommitment := zkcertificate.HashFromBigInt(big.NewInt(7))
+
+	content, err := zkcertificate.SimpleJSON{"name": "John Doe", "age": "30"}.FFEncode()
+	require.NoError(t, err)
+
+	contentHash, err := content.Hash()
+	require.NoError(t, err)
+
+	signature, err := zkcertificate.SignCertificate(privateKey, contentHash, holderCommitment)
+	require.NoError(t, err)
+
+	certificate, err := zkcertificate.New(holderCommitment, content, privateKey.Public(), signature, 1, time.Unix(1700000000, 0))
+	require.NoError(t, err)
+
+	return certificate, privateKey
+}
@@ -0,0 +1,73 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package codec
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+// ToQRPayload returns a compact representation of the certificate suitable for embedding into a QR code.
+// The certificate is CBOR encoded, compressed with zlib and encoded with unpadded base64url.
+// The field hashes of certificate content barely compress, so the payload size is dominated by the content.
+// A KYC certificate encodes to at most about 1300 characters, which fits into a QR code of version 27
+// in byte mode with low error correction.
+func ToQRPayload[T any](c *zkcertificate.Certificate[T]) (string, error) {
+	data, err := Encode(c, FormatCBOR)
+	if err != nil {
+		return "", fmt.Errorf("encode certificate: %w", err)
+	}
+
+	var buf bytes.Buffer
+	w, err := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+	if err != nil {
+		return "", fmt.Errorf("create zlib writer: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return "", fmt.Errorf("compress certificate: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("compress certificate: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// FromQRPayload decodes a certificate from a payload created by ToQRPayload.
+func FromQRPayload[T zkcertificate.Content](payload string) (*zkcertificate.Certificate[T], error) {
+	compressed, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("decode base64: %w", err)
+	}
+
+	r, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("create zlib reader: %w", err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("decompress certificate: %w", err)
+	}
+
+	return Decode[T](data, FormatCBOR)
+}
@@ -0,0 +1,100 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package codec_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate/codec"
+)
+
+func TestToQRPayload(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	payload, err := codec.ToQRPayload(certificate)
+	require.NoError(t, err)
+	require.NotContains(t, payload, "=")
+
+	decoded, err := codec.FromQRPayload[zkcertificate.SimpleJSONContent](payload)
+	require.NoError(t, err)
+	require.Equal(t, certificate.DID, decoded.DID)
+	require.Equal(t, certificate.LeafHash, decoded.LeafHash)
+	require.Equal(t, certificate.Content, decoded.Content)
+	require.True(t, certificate.Provider.Equal(decoded.Provider))
+}
+
+func TestToQRPayload_kyc(t *testing.T) {
+	content, err := zkcertificate.KYCInputs{
+		Surname:           "Doe",
+		Forename:          "John",
+		MiddleName:        "Michael",
+		YearOfBirth:       1989,
+		MonthOfBirth:      5,
+		DayOfBirth:        28,
+		Citizenship:       "SWE",
+		VerificationLevel: 1,
+		StreetAndNumber:   "Bergstrasse 2",
+		Postcode:          "9490",
+		Town:              "Vaduz",
+		Region:            "LI-11",
+		Country:           "LIE",
+	}.FFEncode()
+	require.NoError(t, err)
+
+	privateKey := babyjub.NewRandPrivKey()
+	holderCommitment := zkcertificate.HashFromBigInt(big.NewInt(7))
+
+	contentHash, err := content.Hash()
+	require.NoError(t, err)
+
+	signature, err := zkcertificate.SignCertificate(privateKey, contentHash, holderCommitment)
+	require.NoError(t, err)
+
+	certificate, err := zkcertificate.New(holderCommitment, content, privateKey.Public(), signature, 1<<62, time.Unix(1900000000, 0))
+	require.NoError(t, err)
+
+	payload, err := codec.ToQRPayload(certificate)
+	require.NoError(t, err)
+	require.LessOrEqual(t, len(payload), 1300)
+
+	decoded, err := codec.FromQRPayload[zkcertificate.KYCContent](payload)
+	require.NoError(t, err)
+	require.Equal(t, certificate.LeafHash, decoded.LeafHash)
+	require.Equal(t, certificate.Content, decoded.Content)
+}
+
+func TestFromQRPayload_invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+	}{
+		{name: "not base64", payload: "not base64!"},
+		{name: "not zlib", payload: "AAAA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := codec.FromQRPayload[zkcertificate.SimpleJSONContent](tt.payload)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// FieldChange describes a change of a single certificate content field.
+// Field is a path to the changed value, where nested object keys are separated
+// by dots and array elements are referenced by their index in square brackets.
+type FieldChange struct {
+	Field    string      `json:"field"`
+	OldValue interface{} `json:"oldValue"`
+	NewValue interface{} `json:"newValue"`
+}
+
+// ContentDiff compares JSON representations of two certificate contents field-by-field
+// and returns the list of changed fields. Fields with equal values are omitted.
+func ContentDiff(before, after Content) ([]FieldChange, error) {
+	if before.Standard() != after.Standard() {
+		return nil, fmt.Errorf("content standards %q and %q differ", before.Standard(), after.Standard())
+	}
+
+	beforeValue, err := decodeContentValue(before)
+	if err != nil {
+		return nil, fmt.Errorf("decode old content: %w", err)
+	}
+
+	afterValue, err := decodeContentValue(after)
+	if err != nil {
+		return nil, fmt.Errorf("decode new content: %w", err)
+	}
+
+	var changes []FieldChange
+	diffContentValues("", beforeValue, afterValue, &changes)
+
+	return changes, nil
+}
+
+func decodeContentValue(content Content) (interface{}, error) {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("encode content to json: %w", err)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("decode content json: %w", err)
+	}
+
+	return value, nil
+}
+
+func diffContentValues(path string, before, after interface{}, changes *[]FieldChange) {
+	beforeObject, isBeforeObject := before.(map[string]interface{})
+	afterObject, isAfterObject := after.(map[string]interface{})
+	if isBeforeObject && isAfterObject {
+		keys := make([]string, 0, len(beforeObject)+len(afterObject))
+		for key := range beforeObject {
+			keys = append(keys, key)
+		}
+		for key := range afterObject {
+			if _, ok := beforeObject[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+
+			diffContentValues(fieldPath, beforeObject[key], afterObject[key], changes)
+		}
+
+		return
+	}
+
+	beforeArray, isBeforeArray := before.([]interface{})
+	afterArray, isAfterArray := after.([]interface{})
+	if isBeforeArray && isAfterArray {
+		for i := 0; i < max(len(beforeArray), len(afterArray)); i++ {
+			var beforeElement, afterElement interface{}
+			if i < len(beforeArray) {
+				beforeElement = beforeArray[i]
+			}
+			if i < len(afterArray) {
+				afterElement = afterArray[i]
+			}
+
+			diffContentValues(path+"["+strconv.Itoa(i)+"]", beforeElement, afterElement, changes)
+		}
+
+		return
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, FieldChange{
+			Field:    path,
+			OldValue: before,
+			NewValue: after,
+		})
+	}
+}
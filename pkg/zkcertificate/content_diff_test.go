@@ -0,0 +1,67 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestContentDiff(t *testing.T) {
+	before := zkcertificate.KYCContent{
+		Surname:     mustHashFromString("1"),
+		Forename:    mustHashFromString("2"),
+		YearOfBirth: 1989,
+	}
+	after := before
+	after.Forename = mustHashFromString("3")
+	after.YearOfBirth = 1990
+
+	changes, err := zkcertificate.ContentDiff(before, after)
+	require.NoError(t, err)
+	require.Equal(t, []zkcertificate.FieldChange{
+		{Field: "forename", OldValue: "2", NewValue: "3"},
+		{Field: "yearOfBirth", OldValue: float64(1989), NewValue: float64(1990)},
+	}, changes)
+}
+
+func TestContentDiff_array(t *testing.T) {
+	before := zkcertificate.SimpleJSONContent{mustHashFromString("1"), mustHashFromString("2")}
+	after := zkcertificate.SimpleJSONContent{mustHashFromString("1"), mustHashFromString("4"), mustHashFromString("5")}
+
+	changes, err := zkcertificate.ContentDiff(before, after)
+	require.NoError(t, err)
+	require.Equal(t, []zkcertificate.FieldChange{
+		{Field: "[1]", OldValue: "2", NewValue: "4"},
+		{Field: "[2]", OldValue: nil, NewValue: "5"},
+	}, changes)
+}
+
+func TestContentDiff_equal(t *testing.T) {
+	content := zkcertificate.SimpleJSONContent{mustHashFromString("1")}
+
+	changes, err := zkcertificate.ContentDiff(content, content)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestContentDiff_differentStandards(t *testing.T) {
+	_, err := zkcertificate.ContentDiff(zkcertificate.KYCContent{}, zkcertificate.SimpleJSONContent{})
+	require.Error(t, err)
+}
@@ -0,0 +1,89 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-iden3-crypto/utils"
+	"golang.org/x/crypto/sha3"
+)
+
+// ContentHashFromFields converts Go values into field elements and hashes them with PoseidonHashN,
+// which binds the amount of fields into the hash.
+//
+// Supported field types are:
+//   - int, int64 and uint64, which must be non-negative;
+//   - string, converted as keccak256(value) mod field modulus;
+//   - time.Time, converted as Unix timestamp;
+//   - Hash and *big.Int, which must be within the field.
+func ContentHashFromFields(fields []interface{}) (Hash, error) {
+	inputs := make([]*big.Int, len(fields))
+
+	for i, field := range fields {
+		input, err := fieldElement(field)
+		if err != nil {
+			return Hash{}, fmt.Errorf("convert field %d: %w", i, err)
+		}
+
+		inputs[i] = input
+	}
+
+	hash, err := PoseidonHashN(inputs)
+	if err != nil {
+		return Hash{}, fmt.Errorf("compute hash: %w", err)
+	}
+
+	return HashFromBigInt(hash), nil
+}
+
+func fieldElement(field interface{}) (*big.Int, error) {
+	var res *big.Int
+
+	switch v := field.(type) {
+	case int:
+		res = big.NewInt(int64(v))
+	case int64:
+		res = big.NewInt(v)
+	case uint64:
+		res = new(big.Int).SetUint64(v)
+	case string:
+		hash := sha3.NewLegacyKeccak256()
+		hash.Write([]byte(v))
+		res = new(big.Int).Mod(new(big.Int).SetBytes(hash.Sum(nil)), constants.Q)
+	case time.Time:
+		res = big.NewInt(v.Unix())
+	case Hash:
+		res = v.BigInt()
+	case *big.Int:
+		if v == nil {
+			return nil, fmt.Errorf("nil big integer")
+		}
+
+		res = new(big.Int).Set(v)
+	default:
+		return nil, fmt.Errorf("unsupported type %T", field)
+	}
+
+	if res.Sign() < 0 || !utils.CheckBigIntInField(res) {
+		return nil, fmt.Errorf("value %s is not in the field", res)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,92 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-iden3-crypto/poseidon"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestContentHashFromFields(t *testing.T) {
+	keccak := sha3.NewLegacyKeccak256()
+	keccak.Write([]byte("John"))
+	nameElement := new(big.Int).Mod(new(big.Int).SetBytes(keccak.Sum(nil)), constants.Q)
+
+	expected, err := poseidon.Hash([]*big.Int{
+		big.NewInt(6 << 8),
+		big.NewInt(1),
+		big.NewInt(2),
+		nameElement,
+		big.NewInt(1700000000),
+		big.NewInt(5),
+		big.NewInt(6),
+	})
+	require.NoError(t, err)
+
+	hash, err := zkcertificate.ContentHashFromFields([]interface{}{
+		int64(1),
+		uint64(2),
+		"John",
+		time.Unix(1700000000, 0),
+		zkcertificate.HashFromBigInt(big.NewInt(5)),
+		big.NewInt(6),
+	})
+	require.NoError(t, err)
+	require.Equal(t, zkcertificate.HashFromBigInt(expected), hash)
+}
+
+func TestContentHashFromFields_manyFields(t *testing.T) {
+	fields := make([]interface{}, 14)
+	inputs := make([]*big.Int, 14)
+	for i := range fields {
+		fields[i] = int64(i)
+		inputs[i] = big.NewInt(int64(i))
+	}
+
+	expected, err := zkcertificate.PoseidonHashN(inputs)
+	require.NoError(t, err)
+
+	hash, err := zkcertificate.ContentHashFromFields(fields)
+	require.NoError(t, err)
+	require.Equal(t, zkcertificate.HashFromBigInt(expected), hash)
+}
+
+func TestContentHashFromFields_invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []interface{}
+	}{
+		{name: "no fields", fields: nil},
+		{name: "negative integer", fields: []interface{}{int64(-1)}},
+		{name: "out of field", fields: []interface{}{constants.Q}},
+		{name: "unsupported type", fields: []interface{}{1.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := zkcertificate.ContentHashFromFields(tt.fields)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+const (
+	didContext                = "https://www.w3.org/ns/did/v1"
+	didJWSContext             = "https://w3id.org/security/suites/jws-2020/v1"
+	didVerificationMethodType = "JsonWebKey2020"
+	didServiceType            = "ZKCertificateRegistry"
+
+	// jwkKeyType and jwkCurve describe a BabyJubJub public key as an octet key pair,
+	// which is how JWK represents compressed twisted Edwards curve keys such as Ed25519.
+	jwkKeyType = "OKP"
+	jwkCurve   = "BabyJubJub"
+)
+
+// DIDDocument represents a W3C DID document describing a zero knowledge certificate.
+type DIDDocument struct {
+	Context            []string                `json:"@context"`
+	ID                 string                  `json:"id"`
+	VerificationMethod []DIDVerificationMethod `json:"verificationMethod"`
+	AssertionMethod    []string                `json:"assertionMethod"`
+	Service            []DIDService            `json:"service"`
+}
+
+// DIDVerificationMethod represents a verification method entry of a DID document.
+type DIDVerificationMethod struct {
+	ID           string        `json:"id"`
+	Type         string        `json:"type"`
+	Controller   string        `json:"controller"`
+	PublicKeyJWK DIDJSONWebKey `json:"publicKeyJwk"`
+}
+
+// DIDJSONWebKey represents a public key in the JSON Web Key format of RFC 7517.
+type DIDJSONWebKey struct {
+	KeyType string `json:"kty"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+}
+
+// DIDService represents a service entry of a DID document.
+type DIDService struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
+}
+
+// GenerateDIDDocument produces a JSON-LD DID document for the issued certificate.
+// The document references the provider's public key as a JsonWebKey2020 verification method, whose JWK
+// holds the compressed key as an octet key pair, and includes a service entry pointing to the given service endpoint.
+func GenerateDIDDocument[T any](cert *IssuedCertificate[T], serviceEndpoint string) ([]byte, error) {
+	if cert.DID == "" {
+		return nil, fmt.Errorf("certificate has no did")
+	}
+
+	endpoint, err := url.Parse(serviceEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("parse service endpoint: %w", err)
+	}
+	if !endpoint.IsAbs() {
+		return nil, fmt.Errorf("service endpoint must be an absolute url")
+	}
+
+	if cert.Provider.PublicKey.X == nil || cert.Provider.PublicKey.Y == nil {
+		return nil, fmt.Errorf("incomplete provider public key")
+	}
+
+	compressedPublicKey := cert.Provider.CompressedPublicKey()
+	verificationMethodID := cert.DID + "#provider"
+
+	document := DIDDocument{
+		Context: []string{didContext, didJWSContext},
+		ID:      cert.DID,
+		VerificationMethod: []DIDVerificationMethod{{
+			ID:         verificationMethodID,
+			Type:       didVerificationMethodType,
+			Controller: cert.DID,
+			PublicKeyJWK: DIDJSONWebKey{
+				KeyType: jwkKeyType,
+				Curve:   jwkCurve,
+				X:       base64.RawURLEncoding.EncodeToString(compressedPublicKey[:]),
+			},
+		}},
+		AssertionMethod: []string{verificationMethodID},
+		Service: []DIDService{{
+			ID:              cert.DID + "#registry",
+			Type:            didServiceType,
+			ServiceEndpoint: endpoint.String(),
+		}},
+	}
+
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("encode did document: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,70 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestGenerateDIDDocument(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+	issued := &zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent]{Certificate: *certificate}
+
+	data, err := zkcertificate.GenerateDIDDocument(issued, "https://guardian.example.com/certificates")
+	require.NoError(t, err)
+
+	var document zkcertificate.DIDDocument
+	require.NoError(t, json.Unmarshal(data, &document))
+
+	compressedPublicKey := certificate.Provider.PublicKey.Compress()
+
+	require.Equal(t, certificate.DID, document.ID)
+	require.Equal(t, []string{"https://www.w3.org/ns/did/v1", "https://w3id.org/security/suites/jws-2020/v1"}, document.Context)
+	require.Len(t, document.VerificationMethod, 1)
+	require.Equal(t, "JsonWebKey2020", document.VerificationMethod[0].Type)
+	require.Equal(t, certificate.DID, document.VerificationMethod[0].Controller)
+	require.Equal(t, zkcertificate.DIDJSONWebKey{
+		KeyType: "OKP",
+		Curve:   "BabyJubJub",
+		X:       base64.RawURLEncoding.EncodeToString(compressedPublicKey[:]),
+	}, document.VerificationMethod[0].PublicKeyJWK)
+	require.Len(t, document.Service, 1)
+	require.Equal(t, "https://guardian.example.com/certificates", document.Service[0].ServiceEndpoint)
+}
+
+func TestGenerateDIDDocument_invalidEndpoint(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+	issued := &zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent]{Certificate: *certificate}
+
+	_, err := zkcertificate.GenerateDIDDocument(issued, "relative/path")
+	require.Error(t, err)
+}
+
+func TestGenerateDIDDocument_noProviderKey(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+	certificate.Provider.PublicKey = babyjub.PublicKey{}
+	issued := &zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent]{Certificate: *certificate}
+
+	_, err := zkcertificate.GenerateDIDDocument(issued, "https://guardian.example.com/certificates")
+	require.Error(t, err)
+}
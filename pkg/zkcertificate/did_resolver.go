@@ -0,0 +1,89 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ContentRegistry is a storage backend that resolves certificates by their leaf hash.
+type ContentRegistry interface {
+	// LookupByLeafHash returns the JSON encoded certificate with the given leaf hash.
+	LookupByLeafHash(leafHash Hash) (json.RawMessage, error)
+}
+
+// ParseDID parses a DID in the "did:standard:leafHash" format produced by DID.
+func ParseDID(did string) (Standard, Hash, error) {
+	parts := strings.Split(did, ":")
+	if len(parts) != 3 || parts[0] != "did" {
+		return "", Hash{}, fmt.Errorf("invalid did %q", did)
+	}
+
+	standard, err := ParseStandard(parts[1])
+	if err != nil {
+		return "", Hash{}, err
+	}
+
+	var leafHash Hash
+	if err := leafHash.UnmarshalText([]byte(parts[2])); err != nil {
+		return "", Hash{}, fmt.Errorf("invalid leaf hash of did %q: %w", did, err)
+	}
+
+	return standard, leafHash, nil
+}
+
+// NewCertificateFromDID resolves the DID to its certificate using the registry.
+// The certificate content is kept as raw JSON. The resolved certificate must match the DID,
+// it must hold complete provider data, and its leaf hash must match the certificate fields.
+func NewCertificateFromDID(did string, registry ContentRegistry) (*Certificate[json.RawMessage], error) {
+	standard, leafHash, err := ParseDID(did)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := registry.LookupByLeafHash(leafHash)
+	if err != nil {
+		return nil, fmt.Errorf("lookup certificate %s: %w", did, err)
+	}
+
+	var certificate Certificate[json.RawMessage]
+	if err := json.Unmarshal(data, &certificate); err != nil {
+		return nil, fmt.Errorf("decode certificate %s: %w", did, err)
+	}
+
+	if certificate.Standard != standard || certificate.DID != did {
+		return nil, fmt.Errorf("resolved certificate %s does not match did %s", certificate.DID, did)
+	}
+
+	if err := certificate.Provider.validate(); err != nil {
+		return nil, err
+	}
+
+	computedLeafHash, err := certificate.computeLeafHash()
+	if err != nil {
+		return nil, fmt.Errorf("compute leaf hash: %w", err)
+	}
+
+	if computedLeafHash.Cmp(leafHash) != 0 {
+		return nil, fmt.Errorf("resolved certificate has invalid leaf hash")
+	}
+
+	certificate.StandardVersion = standardVersion(standard)
+
+	return &certificate, nil
+}
@@ -0,0 +1,112 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+type mapContentRegistry map[string]json.RawMessage
+
+func (r mapContentRegistry) LookupByLeafHash(leafHash zkcertificate.Hash) (json.RawMessage, error) {
+	data, ok := r[leafHash.String()]
+	if !ok {
+		return nil, zkcertificate.ErrCertificateNotFound
+	}
+
+	return data, nil
+}
+
+func TestParseDID(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	standard, leafHash, err := zkcertificate.ParseDID(certificate.DID)
+	require.NoError(t, err)
+	require.Equal(t, certificate.Standard, standard)
+	require.Equal(t, certificate.LeafHash, leafHash)
+
+	for _, did := range []string{"", "did:gip2", "foo:gip2:1", "did:unknown:1", "did:gip2:abc", "did:gip2:1:2"} {
+		_, _, err := zkcertificate.ParseDID(did)
+		require.Error(t, err, did)
+	}
+}
+
+func TestNewCertificateFromDID(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	data, err := json.Marshal(certificate)
+	require.NoError(t, err)
+
+	registry := mapContentRegistry{certificate.LeafHash.String(): data}
+
+	resolved, err := zkcertificate.NewCertificateFromDID(certificate.DID, registry)
+	require.NoError(t, err)
+	require.Equal(t, certificate.DID, resolved.DID)
+	require.Equal(t, certificate.ContentHash, resolved.ContentHash)
+
+	expectedContent, err := certificate.ContentJSON()
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedContent), string(resolved.Content))
+
+	_, err = zkcertificate.NewCertificateFromDID(
+		zkcertificate.DID(certificate.Standard, zkcertificate.HashFromBigInt(big.NewInt(1))),
+		registry,
+	)
+	require.ErrorIs(t, err, zkcertificate.ErrCertificateNotFound)
+}
+
+func TestNewCertificateFromDID_tampered(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	tampered := certificate.Clone()
+	tampered.RandomSalt++
+
+	data, err := json.Marshal(tampered)
+	require.NoError(t, err)
+
+	registry := mapContentRegistry{certificate.LeafHash.String(): data}
+
+	_, err = zkcertificate.NewCertificateFromDID(certificate.DID, registry)
+	require.ErrorContains(t, err, "invalid leaf hash")
+}
+
+func TestNewCertificateFromDID_missingProviderData(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	data, err := json.Marshal(certificate)
+	require.NoError(t, err)
+
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &fields))
+	delete(fields, "providerData")
+
+	data, err = json.Marshal(fields)
+	require.NoError(t, err)
+
+	registry := mapContentRegistry{certificate.LeafHash.String(): data}
+
+	_, err = zkcertificate.NewCertificateFromDID(certificate.DID, registry)
+
+	var certificateError *zkcertificate.CertificateError
+	require.ErrorAs(t, err, &certificateError)
+	require.Equal(t, zkcertificate.InvalidProviderData, certificateError.Kind)
+}
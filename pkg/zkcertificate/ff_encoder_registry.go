@@ -0,0 +1,120 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// FFEncoderRegistry maps certificate standards to the FFEncoder implementations of their inputs,
+// so inputs of any registered standard can be encoded without a compile-time type switch.
+// It is safe for concurrent use.
+type FFEncoderRegistry struct {
+	mu       sync.RWMutex
+	encoders map[Standard]func(raw interface{}) (Content, error)
+}
+
+// DefaultFFEncoderRegistry holds the encoders of all built-in standards.
+var DefaultFFEncoderRegistry = newDefaultFFEncoderRegistry()
+
+// NewFFEncoderRegistry creates an empty registry.
+func NewFFEncoderRegistry() *FFEncoderRegistry {
+	return &FFEncoderRegistry{encoders: make(map[Standard]func(raw interface{}) (Content, error))}
+}
+
+func newDefaultFFEncoderRegistry() *FFEncoderRegistry {
+	r := NewFFEncoderRegistry()
+
+	if err := RegisterFFEncoder(r, StandardKYC, func() FFEncoder[KYCContent] { return &KYCInputs{} }); err != nil {
+		panic(err)
+	}
+
+	if err := RegisterFFEncoder(r, StandardSimpleJSON, func() FFEncoder[SimpleJSONContent] { return &SimpleJSON{} }); err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// RegisterFFEncoder registers the encoder factory of the given standard.
+// The factory must return a pointer, so the raw inputs can be decoded into the encoder.
+// It is a function rather than a method, because Go methods can not have type parameters.
+func RegisterFFEncoder[T Content](r *FFEncoderRegistry, s Standard, factory func() FFEncoder[T]) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.encoders[s]; ok {
+		return fmt.Errorf("encoder of standard %q is already registered", s)
+	}
+
+	r.encoders[s] = func(raw interface{}) (Content, error) {
+		encoder := factory()
+		if err := decodeRawInputs(raw, encoder); err != nil {
+			return nil, err
+		}
+
+		return encoder.FFEncode()
+	}
+
+	return nil
+}
+
+// Encode decodes the raw inputs with the encoder registered for the standard and encodes them to certificate content.
+// Raw inputs are JSON given as []byte or json.RawMessage, or any value that is JSON encoded first.
+func (r *FFEncoderRegistry) Encode(s Standard, raw interface{}) (Content, error) {
+	r.mu.RLock()
+	encode, ok := r.encoders[s]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w %q: no encoder registered", ErrUnknownStandard, s)
+	}
+
+	content, err := encode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("encode inputs of standard %q: %w", s, err)
+	}
+
+	if standard := content.Standard(); standard != s {
+		return nil, fmt.Errorf("encoder of standard %q produced content of standard %q", s, standard)
+	}
+
+	return content, nil
+}
+
+func decodeRawInputs(raw interface{}, target interface{}) error {
+	var data []byte
+
+	switch v := raw.(type) {
+	case []byte:
+		data = v
+	case json.RawMessage:
+		data = v
+	default:
+		var err error
+		if data, err = json.Marshal(raw); err != nil {
+			return fmt.Errorf("encode raw inputs: %w", err)
+		}
+	}
+
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("decode inputs: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestFFEncoderRegistry_Encode(t *testing.T) {
+	kycInputs := zkcertificate.KYCInputs{
+		Surname:           "Doe",
+		Forename:          "John",
+		YearOfBirth:       1989,
+		MonthOfBirth:      5,
+		DayOfBirth:        28,
+		Citizenship:       "SWE",
+		VerificationLevel: 1,
+		StreetAndNumber:   "Bergstrasse 2",
+		Postcode:          "9490",
+		Town:              "Vaduz",
+		Country:           "LIE",
+	}
+	expectedKYCContent, err := kycInputs.FFEncode()
+	require.NoError(t, err)
+
+	simpleJSON := zkcertificate.SimpleJSON{"name": "John Doe", "age": "30"}
+	expectedSimpleJSONContent, err := simpleJSON.FFEncode()
+	require.NoError(t, err)
+
+	simpleJSONData, err := json.Marshal(simpleJSON)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		standard zkcertificate.Standard
+		raw      interface{}
+		expected zkcertificate.Content
+	}{
+		{
+			name:     "kyc from struct",
+			standard: zkcertificate.StandardKYC,
+			raw:      kycInputs,
+			expected: expectedKYCContent,
+		},
+		{
+			name:     "simple json from raw message",
+			standard: zkcertificate.StandardSimpleJSON,
+			raw:      json.RawMessage(simpleJSONData),
+			expected: expectedSimpleJSONContent,
+		},
+		{
+			name:     "simple json from bytes",
+			standard: zkcertificate.StandardSimpleJSON,
+			raw:      simpleJSONData,
+			expected: expectedSimpleJSONContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := zkcertificate.DefaultFFEncoderRegistry.Encode(tt.standard, tt.raw)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, content)
+		})
+	}
+}
+
+func TestFFEncoderRegistry_errors(t *testing.T) {
+	registry := zkcertificate.NewFFEncoderRegistry()
+
+	_, err := registry.Encode(zkcertificate.StandardSimpleJSON, []byte(`{}`))
+	require.ErrorIs(t, err, zkcertificate.ErrUnknownStandard)
+
+	factory := func() zkcertificate.FFEncoder[zkcertificate.SimpleJSONContent] { return &zkcertificate.SimpleJSON{} }
+	require.NoError(t, zkcertificate.RegisterFFEncoder(registry, zkcertificate.StandardSimpleJSON, factory))
+	require.Error(t, zkcertificate.RegisterFFEncoder(registry, zkcertificate.StandardSimpleJSON, factory))
+
+	_, err = registry.Encode(zkcertificate.StandardSimpleJSON, []byte(`not json`))
+	require.Error(t, err)
+
+	require.NoError(t, zkcertificate.RegisterFFEncoder(registry, zkcertificate.StandardKYC, factory))
+	_, err = registry.Encode(zkcertificate.StandardKYC, []byte(`{"name":"John Doe"}`))
+	require.ErrorContains(t, err, "produced content of standard")
+}
@@ -16,7 +16,12 @@
 package zkcertificate
 
 import (
+	"cmp"
+	"fmt"
 	"math/big"
+	"math/bits"
+
+	"github.com/iden3/go-iden3-crypto/utils"
 )
 
 // Hash represents a cryptographic hash value obtained by Poseidon algorithm.
@@ -31,13 +36,47 @@ func HashFromBigInt(n *big.Int) Hash {
 	return Hash(*n)
 }
 
+// HashFromBytes32 creates a Hash from a 32-byte big-endian representation of a field element.
+func HashFromBytes32(b [32]byte) (Hash, error) {
+	res := new(big.Int).SetBytes(b[:])
+	if !utils.CheckBigIntInField(res) {
+		return Hash{}, fmt.Errorf("value %s is not in the field", res)
+	}
+
+	return Hash(*res), nil
+}
+
+// ValidateHash returns an error if the hash is not a valid element of the BN254 scalar field,
+// which is the base field of Baby Jubjub and the domain of Poseidon.
+func ValidateHash(h Hash) error {
+	if !h.IsInField() {
+		return fmt.Errorf("hash %s is not in the field", h)
+	}
+
+	return nil
+}
+
+// cloneHash returns a copy of the Hash that does not share memory with the original.
+func cloneHash(h Hash) Hash {
+	return Hash(*new(big.Int).Set(h.BigInt()))
+}
+
+// IsInField reports whether the hash is a non-negative value less than the field modulus.
+// Hashes outside of the field are rejected by Poseidon and by the circuits.
+func (h Hash) IsInField() bool {
+	n := big.Int(h)
+	return n.Sign() >= 0 && utils.CheckBigIntInField(&n)
+}
+
 // BigInt converts a Hash value to a big.Int.
 func (h Hash) BigInt() *big.Int {
 	n := big.Int(h)
 	return &n
 }
 
-// Bytes32 converts a Hash value to a 32-byte array.
+// Bytes32 converts a Hash value to a 32-byte array in big-endian order, padded with leading zeros.
+// The hash must be in the field, see ValidateHash: Bytes32 panics on values that do not fit into 256 bits
+// and encodes negative values as their absolute value.
 func (h Hash) Bytes32() [32]byte {
 	var res [32]byte
 	h.BigInt().FillBytes(res[:])
@@ -45,6 +84,74 @@ func (h Hash) Bytes32() [32]byte {
 	return res
 }
 
+// XOR returns the bitwise XOR of the 32-byte representations of both hashes.
+// Both hashes must be in the field, but the result is not guaranteed to be within the field.
+func (h Hash) XOR(other Hash) (Hash, error) {
+	if err := ValidateHash(h); err != nil {
+		return Hash{}, err
+	}
+
+	if err := ValidateHash(other); err != nil {
+		return Hash{}, err
+	}
+
+	a, b := h.Bytes32(), other.Bytes32()
+
+	var res [32]byte
+	for i := range res {
+		res[i] = a[i] ^ b[i]
+	}
+
+	return Hash(*new(big.Int).SetBytes(res[:])), nil
+}
+
+// Cmp compares both hashes and returns -1, 0 or +1 if h is less than, equal to or greater than other,
+// with the same result as big.Int.Cmp. The signs are compared first, then the absolute values.
+// It compares the machine words of both values directly without allocating, and always visits all words
+// of a 256-bit value, so the number of operations does not depend on the position of the first difference.
+func (h Hash) Cmp(other Hash) int {
+	a, b := big.Int(h), big.Int(other)
+
+	sign := a.Sign()
+	if otherSign := b.Sign(); sign != otherSign {
+		return cmp.Compare(sign, otherSign)
+	}
+
+	x, y := a.Bits(), b.Bits()
+
+	n := max(len(x), len(y), hashWords)
+
+	var greater, less uint
+	for i := n - 1; i >= 0; i-- {
+		xi, yi := wordAt(x, i), wordAt(y, i)
+
+		_, gt := bits.Sub(yi, xi, 0)
+		_, lt := bits.Sub(xi, yi, 0)
+
+		undecided := 1 ^ (greater | less)
+		greater |= undecided & gt
+		less |= undecided & lt
+	}
+
+	if sign < 0 {
+		return int(less) - int(greater)
+	}
+
+	return int(greater) - int(less)
+}
+
+// hashWords is the number of machine words of a 256-bit value.
+const hashWords = 256 / bits.UintSize
+
+// wordAt returns the i-th least significant word of a big.Int absolute value, or zero beyond its length.
+func wordAt(words []big.Word, i int) uint {
+	if i < len(words) {
+		return uint(words[i])
+	}
+
+	return 0
+}
+
 // String returns the string representation of the Hash value.
 func (h Hash) String() string {
 	return h.BigInt().String()
@@ -65,3 +172,30 @@ func (h *Hash) UnmarshalText(text []byte) error {
 func (h Hash) MarshalText() (text []byte, err error) {
 	return h.BigInt().MarshalText()
 }
+
+// MarshalBinary implements [encoding.BinaryMarshaler].
+// The Hash is encoded as a 32-byte big-endian representation of the field element.
+// Hashes outside of the field are rejected.
+func (h Hash) MarshalBinary() (data []byte, err error) {
+	if err := ValidateHash(h); err != nil {
+		return nil, err
+	}
+
+	res := h.Bytes32()
+	return res[:], nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler].
+func (h *Hash) UnmarshalBinary(data []byte) error {
+	if len(data) != 32 {
+		return fmt.Errorf("invalid data length %d, want 32", len(data))
+	}
+
+	res, err := HashFromBytes32([32]byte(data))
+	if err != nil {
+		return err
+	}
+
+	*h = res
+	return nil
+}
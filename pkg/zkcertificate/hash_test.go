@@ -20,6 +20,8 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/iden3/go-iden3-crypto/constants"
 	"github.com/stretchr/testify/require"
 
 	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
@@ -31,6 +33,126 @@ func TestHash_Bytes32(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+func TestHashFromBytes32(t *testing.T) {
+	hash, err := zkcertificate.HashFromBytes32([32]byte{30: 0x3, 31: 0x15})
+	require.NoError(t, err)
+	require.Equal(t, "789", hash.String())
+	require.Equal(t, [32]byte{30: 0x3, 31: 0x15}, hash.Bytes32())
+
+	var modulus [32]byte
+	constants.Q.FillBytes(modulus[:])
+
+	_, err = zkcertificate.HashFromBytes32(modulus)
+	require.Error(t, err)
+}
+
+func TestHash_XOR(t *testing.T) {
+	hash := zkcertificate.HashFromBigInt(big.NewInt(0b1100))
+	mask := zkcertificate.HashFromBigInt(big.NewInt(0b1010))
+
+	blinded, err := hash.XOR(mask)
+	require.NoError(t, err)
+	require.Equal(t, "6", blinded.String())
+
+	unblinded, err := blinded.XOR(mask)
+	require.NoError(t, err)
+	require.Equal(t, hash, unblinded)
+
+	zero, err := hash.XOR(hash)
+	require.NoError(t, err)
+	require.Equal(t, "0", zero.String())
+
+	_, err = hash.XOR(zkcertificate.HashFromBigInt(big.NewInt(-1)))
+	require.Error(t, err)
+
+	_, err = zkcertificate.HashFromBigInt(new(big.Int).Lsh(big.NewInt(1), 300)).XOR(mask)
+	require.Error(t, err)
+}
+
+func TestHash_Cmp(t *testing.T) {
+	large, ok := new(big.Int).SetString("21888242871839275222246405745257275088548364400416417784180001234567890", 10)
+	require.True(t, ok)
+
+	tests := []struct {
+		name string
+		a    *big.Int
+		b    *big.Int
+		want int
+	}{
+		{name: "equal", a: big.NewInt(42), b: big.NewInt(42), want: 0},
+		{name: "less", a: big.NewInt(255), b: big.NewInt(256), want: -1},
+		{name: "greater", a: big.NewInt(256), b: big.NewInt(255), want: 1},
+		{name: "zero", a: big.NewInt(0), b: big.NewInt(1), want: -1},
+		{name: "large", a: large, b: big.NewInt(1), want: 1},
+		{name: "differ in last byte", a: new(big.Int).Add(large, big.NewInt(1)), b: large, want: 1},
+		{name: "above 256 bits", a: new(big.Int).Lsh(big.NewInt(1), 300), b: large, want: 1},
+		{name: "negated", a: big.NewInt(-42), b: big.NewInt(42), want: -1},
+		{name: "negative and zero", a: big.NewInt(-1), b: big.NewInt(0), want: -1},
+		{name: "both negative", a: big.NewInt(-256), b: big.NewInt(-255), want: -1},
+		{name: "equal negative", a: big.NewInt(-42), b: big.NewInt(-42), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := zkcertificate.HashFromBigInt(tt.a), zkcertificate.HashFromBigInt(tt.b)
+
+			require.Equal(t, tt.want, a.Cmp(b))
+			require.Equal(t, -tt.want, b.Cmp(a))
+			require.Equal(t, tt.a.Cmp(tt.b), a.Cmp(b))
+		})
+	}
+}
+
+func TestHash_Cmp_allocations(t *testing.T) {
+	a := zkcertificate.HashFromBigInt(big.NewInt(1))
+	b := zkcertificate.HashFromBigInt(big.NewInt(2))
+
+	require.Zero(t, testing.AllocsPerRun(100, func() {
+		a.Cmp(b)
+	}))
+}
+
+func TestHash_IsInField(t *testing.T) {
+	tests := []struct {
+		name string
+		n    *big.Int
+		want bool
+	}{
+		{name: "zero", n: big.NewInt(0), want: true},
+		{name: "above sub order", n: new(big.Int).Add(babyjub.SubOrder, big.NewInt(1)), want: true},
+		{name: "largest element", n: new(big.Int).Sub(constants.Q, big.NewInt(1)), want: true},
+		{name: "field modulus", n: constants.Q, want: false},
+		{name: "negative", n: big.NewInt(-1), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash := zkcertificate.HashFromBigInt(tt.n)
+
+			require.Equal(t, tt.want, hash.IsInField())
+			if tt.want {
+				require.NoError(t, zkcertificate.ValidateHash(hash))
+			} else {
+				require.Error(t, zkcertificate.ValidateHash(hash))
+			}
+		})
+	}
+}
+
+func TestSignCertificate_hashNotInField(t *testing.T) {
+	privateKey := babyjub.NewRandPrivKey()
+
+	_, err := zkcertificate.SignCertificate(
+		privateKey,
+		zkcertificate.HashFromBigInt(constants.Q),
+		zkcertificate.HashFromBigInt(big.NewInt(7)),
+	)
+
+	var certificateError *zkcertificate.CertificateError
+	require.ErrorAs(t, err, &certificateError)
+	require.Equal(t, zkcertificate.InvalidArgument, certificateError.Kind)
+}
+
 func TestHash_String(t *testing.T) {
 	actual := zkcertificate.HashFromBigInt(big.NewInt(101112)).String()
 	require.Equal(t, "101112", actual)
@@ -47,3 +169,51 @@ func TestHash_JSON(t *testing.T) {
 	require.NoError(t, json.Unmarshal(data, &deserialized))
 	require.Equal(t, hash, deserialized)
 }
+
+func TestHash_Binary(t *testing.T) {
+	hash := zkcertificate.HashFromBigInt(big.NewInt(161718))
+
+	data, err := hash.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, data, 32)
+
+	var deserialized zkcertificate.Hash
+	require.NoError(t, deserialized.UnmarshalBinary(data))
+	require.Equal(t, hash, deserialized)
+}
+
+func TestHash_MarshalBinary_invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		value *big.Int
+	}{
+		{name: "Negative", value: big.NewInt(-161718)},
+		{name: "Out of field", value: constants.Q},
+		{name: "Above 256 bits", value: new(big.Int).Lsh(big.NewInt(1), 300)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := zkcertificate.HashFromBigInt(tt.value).MarshalBinary()
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestHash_UnmarshalBinary_invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "Too short", data: make([]byte, 31)},
+		{name: "Too long", data: make([]byte, 33)},
+		{name: "Out of field", data: constants.Q.FillBytes(make([]byte, 32))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hash zkcertificate.Hash
+			require.Error(t, hash.UnmarshalBinary(tt.data))
+		})
+	}
+}
@@ -16,8 +16,15 @@
 package zkcertificate
 
 import (
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-iden3-crypto/poseidon"
+	"github.com/iden3/go-iden3-crypto/utils"
 
 	"github.com/galactica-corp/guardians-sdk/internal/validation"
 )
@@ -51,3 +58,43 @@ func (c *HolderCommitment) UnmarshalJSON(data []byte) error {
 	*c = HolderCommitment(alias)
 	return nil
 }
+
+// ValidateHolderCommitment checks that the holder commitment is a field element.
+// Commitments are Poseidon outputs, so they range over the whole field.
+func ValidateHolderCommitment(commitment Hash) error {
+	if !commitment.IsInField() {
+		return fmt.Errorf("holder commitment %s is not in the field", commitment)
+	}
+
+	return nil
+}
+
+// GenerateHolderCommitment derives a holder commitment from the holder secret
+// as Poseidon(holderSecret), matching the derivation expected by the ZK circuits.
+func GenerateHolderCommitment(holderSecret *big.Int) (Hash, error) {
+	if holderSecret == nil || holderSecret.Sign() < 0 || !utils.CheckBigIntInField(holderSecret) {
+		return Hash{}, fmt.Errorf("holder secret is not in the field")
+	}
+
+	commitment, err := poseidon.Hash([]*big.Int{holderSecret})
+	if err != nil {
+		return Hash{}, fmt.Errorf("hash holder secret: %w", err)
+	}
+
+	return HashFromBigInt(commitment), nil
+}
+
+// GenerateHolderSecret generates a random holder secret within the Baby Jubjub field.
+func GenerateHolderSecret() (*big.Int, error) {
+	return randomFieldElement()
+}
+
+// randomFieldElement generates a random element of the Baby Jubjub field.
+func randomFieldElement() (*big.Int, error) {
+	var buf [32]byte
+	if _, err := io.ReadFull(rand.Reader, buf[:]); err != nil {
+		return nil, fmt.Errorf("read random bytes: %w", err)
+	}
+
+	return new(big.Int).Mod(new(big.Int).SetBytes(buf[:]), constants.Q), nil
+}
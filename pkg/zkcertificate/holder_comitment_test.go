@@ -22,6 +22,9 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-iden3-crypto/poseidon"
 	"github.com/stretchr/testify/require"
 
 	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
@@ -75,6 +78,26 @@ func TestHolderCommitment_JSON(t *testing.T) {
 	require.Equal(t, holderCommitment, deserialized)
 }
 
+func TestGenerateHolderCommitment(t *testing.T) {
+	secret, err := zkcertificate.GenerateHolderSecret()
+	require.NoError(t, err)
+
+	commitment, err := zkcertificate.GenerateHolderCommitment(secret)
+	require.NoError(t, err)
+
+	expected, err := poseidon.Hash([]*big.Int{secret})
+	require.NoError(t, err)
+	require.Equal(t, zkcertificate.HashFromBigInt(expected), commitment)
+}
+
+func TestGenerateHolderCommitment_invalid(t *testing.T) {
+	_, err := zkcertificate.GenerateHolderCommitment(nil)
+	require.Error(t, err)
+
+	_, err = zkcertificate.GenerateHolderCommitment(constants.Q)
+	require.Error(t, err)
+}
+
 func mustDecodeBase64(s string) []byte {
 	res, err := base64.StdEncoding.DecodeString(s)
 	if err != nil {
@@ -83,3 +106,45 @@ func mustDecodeBase64(s string) []byte {
 
 	return res
 }
+
+func TestValidateHolderCommitment(t *testing.T) {
+	tests := []struct {
+		name       string
+		commitment *big.Int
+		wantErr    bool
+	}{
+		{
+			name:       "small commitment",
+			commitment: big.NewInt(7),
+		},
+		{
+			name:       "subgroup order",
+			commitment: babyjub.SubOrder,
+		},
+		{
+			name:       "largest valid commitment",
+			commitment: new(big.Int).Sub(constants.Q, big.NewInt(1)),
+		},
+		{
+			name:       "field modulus",
+			commitment: constants.Q,
+			wantErr:    true,
+		},
+		{
+			name:       "negative",
+			commitment: big.NewInt(-1),
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := zkcertificate.ValidateHolderCommitment(zkcertificate.HashFromBigInt(tt.commitment))
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"time"
+
+	"github.com/iden3/go-iden3-crypto/babyjub"
+)
+
+// IssuedCertificateSet is an immutable collection of issued certificates with chainable filters.
+// Filters return new sets and never modify the receiver.
+type IssuedCertificateSet[T Content] struct {
+	certificates []*IssuedCertificate[T]
+}
+
+// NewIssuedCertificateSet creates a set of the given certificates.
+func NewIssuedCertificateSet[T Content](certificates ...*IssuedCertificate[T]) IssuedCertificateSet[T] {
+	return IssuedCertificateSet[T]{certificates: certificates}
+}
+
+// FilterByStandard returns the certificates of the given standard.
+func (s IssuedCertificateSet[T]) FilterByStandard(standard Standard) IssuedCertificateSet[T] {
+	return s.filter(func(certificate *IssuedCertificate[T]) bool {
+		return certificate.Standard == standard
+	})
+}
+
+// FilterByProvider returns the certificates issued by the provider with the given public key.
+func (s IssuedCertificateSet[T]) FilterByProvider(providerKey *babyjub.PublicKey) IssuedCertificateSet[T] {
+	return s.filter(func(certificate *IssuedCertificate[T]) bool {
+		return providerKey != nil && pointsEqual(certificate.Provider.PublicKey.Point(), providerKey.Point())
+	})
+}
+
+// FilterExpiredBefore returns the certificates whose expiration date is before the given time.
+func (s IssuedCertificateSet[T]) FilterExpiredBefore(t time.Time) IssuedCertificateSet[T] {
+	return s.filter(func(certificate *IssuedCertificate[T]) bool {
+		return time.Time(certificate.ExpirationDate).Before(t)
+	})
+}
+
+// Len returns the amount of certificates in the set.
+func (s IssuedCertificateSet[T]) Len() int {
+	return len(s.certificates)
+}
+
+// Each calls fn for every certificate in the set in insertion order until fn returns false.
+func (s IssuedCertificateSet[T]) Each(fn func(*IssuedCertificate[T]) bool) {
+	for _, certificate := range s.certificates {
+		if !fn(certificate) {
+			return
+		}
+	}
+}
+
+func (s IssuedCertificateSet[T]) filter(keep func(*IssuedCertificate[T]) bool) IssuedCertificateSet[T] {
+	var res []*IssuedCertificate[T]
+
+	for _, certificate := range s.certificates {
+		if keep(certificate) {
+			res = append(res, certificate)
+		}
+	}
+
+	return IssuedCertificateSet[T]{certificates: res}
+}
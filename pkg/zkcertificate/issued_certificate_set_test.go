@@ -0,0 +1,73 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestIssuedCertificateSet(t *testing.T) {
+	first := makeIssuedCertificate(t)
+	second := makeIssuedCertificate(t)
+	second.ExpirationDate = zkcertificate.Timestamp(time.Unix(1900000000, 0))
+	third := makeIssuedCertificate(t)
+	third.Standard = zkcertificate.StandardKYC
+
+	set := zkcertificate.NewIssuedCertificateSet(first, second, third)
+	require.Equal(t, 3, set.Len())
+
+	require.Equal(t, 2, set.FilterByStandard(zkcertificate.StandardSimpleJSON).Len())
+	require.Equal(t, 1, set.FilterByProvider(&second.Provider.PublicKey).Len())
+	otherKey := babyjub.NewRandPrivKey()
+	require.Zero(t, set.FilterByProvider(otherKey.Public()).Len())
+	require.Zero(t, set.FilterByProvider(nil).Len())
+
+	expired := set.FilterExpiredBefore(time.Unix(1800000000, 0))
+	require.Equal(t, 2, expired.Len())
+
+	chained := set.
+		FilterByStandard(zkcertificate.StandardSimpleJSON).
+		FilterExpiredBefore(time.Unix(1800000000, 0))
+	require.Equal(t, 1, chained.Len())
+
+	var got []*zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent]
+	chained.Each(func(certificate *zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent]) bool {
+		got = append(got, certificate)
+		return true
+	})
+	require.Equal(t, []*zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent]{first}, got)
+}
+
+func TestIssuedCertificateSet_Each_earlyExit(t *testing.T) {
+	set := zkcertificate.NewIssuedCertificateSet(
+		makeIssuedCertificate(t),
+		makeIssuedCertificate(t),
+		makeIssuedCertificate(t),
+	)
+
+	calls := 0
+	set.Each(func(*zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent]) bool {
+		calls++
+		return calls < 2
+	})
+	require.Equal(t, 2, calls)
+}
@@ -0,0 +1,70 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+// Package jwtcert transports zero-knowledge certificates in JSON Web Tokens.
+package jwtcert
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+// certificateClaims are the JWT claims used to transport a certificate.
+type certificateClaims[T any] struct {
+	Certificate *zkcertificate.Certificate[T] `json:"zkcert"`
+	jwt.RegisteredClaims
+}
+
+// Sign encodes the certificate as the "zkcert" claim of a JWT signed with the given key and method.
+// The "exp" claim is set to the certificate expiration date.
+func Sign[T any](c *zkcertificate.Certificate[T], signingKey interface{}, method jwt.SigningMethod) (string, error) {
+	claims := certificateClaims[T]{
+		Certificate: c,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Time(c.ExpirationDate)),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(method, claims).SignedString(signingKey)
+	if err != nil {
+		return "", fmt.Errorf("sign token: %w", err)
+	}
+
+	return token, nil
+}
+
+// Parse decodes a certificate from a JWT created with Sign.
+// The token signature is verified with the key returned by keyFunc, and tokens past their "exp" claim are rejected.
+func Parse[T zkcertificate.Content](tokenString string, keyFunc jwt.Keyfunc) (*zkcertificate.Certificate[T], error) {
+	var claims certificateClaims[T]
+
+	if _, err := jwt.ParseWithClaims(tokenString, &claims, keyFunc, jwt.WithExpirationRequired()); err != nil {
+		return nil, fmt.Errorf("parse token: %w", err)
+	}
+
+	if claims.Certificate == nil {
+		return nil, fmt.Errorf("token has no zkcert claim")
+	}
+
+	if claims.ExpiresAt.Unix() != time.Time(claims.Certificate.ExpirationDate).Unix() {
+		return nil, fmt.Errorf("token expiration does not match certificate expiration date")
+	}
+
+	return claims.Certificate, nil
+}
@@ -0,0 +1,96 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package jwtcert_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate/jwtcert"
+)
+
+func TestSign(t *testing.T) {
+	secret := []byte("test secret")
+	keyFunc := func(*jwt.Token) (interface{}, error) { return secret, nil }
+
+	certificate := makeCertificate(t)
+	certificate.ExpirationDate = zkcertificate.Timestamp(time.Now().Add(time.Hour).Truncate(time.Second))
+
+	token, err := jwtcert.Sign(certificate, secret, jwt.SigningMethodHS256)
+	require.NoError(t, err)
+
+	parsed, err := jwtcert.Parse[zkcertificate.SimpleJSONContent](token, keyFunc)
+	require.NoError(t, err)
+	require.Equal(t, certificate.DID, parsed.DID)
+	require.Equal(t, certificate.LeafHash, parsed.LeafHash)
+	require.Equal(t, time.Time(certificate.ExpirationDate).Unix(), time.Time(parsed.ExpirationDate).Unix())
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, keyFunc)
+	require.NoError(t, err)
+
+	exp, err := claims.GetExpirationTime()
+	require.NoError(t, err)
+	require.Equal(t, time.Time(certificate.ExpirationDate).Unix(), exp.Unix())
+
+	_, err = jwtcert.Parse[zkcertificate.SimpleJSONContent](
+		token,
+		func(*jwt.Token) (interface{}, error) { return []byte("other secret"), nil },
+	)
+	require.ErrorIs(t, err, jwt.ErrSignatureInvalid)
+}
+
+func TestParse_expired(t *testing.T) {
+	secret := []byte("test secret")
+
+	certificate := makeCertificate(t)
+
+	token, err := jwtcert.Sign(certificate, secret, jwt.SigningMethodHS256)
+	require.NoError(t, err)
+
+	_, err = jwtcert.Parse[zkcertificate.SimpleJSONContent](
+		token,
+		func(*jwt.Token) (interface{}, error) { return secret, nil },
+	)
+	require.ErrorIs(t, err, jwt.ErrTokenExpired)
+}
+
+func makeCertificate(t *testing.T) *zkcertificate.Certificate[zkcertificate.SimpleJSONContent] {
+	t.Helper()
+
+	privateKey := babyjub.NewRandPrivKey()
+	holderCommitment := zkcertificate.HashFromBigInt(big.NewInt(7))
+
+	content, err := zkcertificate.SimpleJSON{"name": "John Doe", "age": "30"}.FFEncode()
+	require.NoError(t, err)
+
+	contentHash, err := content.Hash()
+	require.NoError(t, err)
+
+	signature, err := zkcertificate.SignCertificate(privateKey, contentHash, holderCommitment)
+	require.NoError(t, err)
+
+	certificate, err := zkcertificate.New(holderCommitment, content, privateKey.Public(), signature, 1, time.Unix(1700000000, 0))
+	require.NoError(t, err)
+
+	return certificate
+}
@@ -0,0 +1,91 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+// Package metrics exposes zero-knowledge certificate statistics as Prometheus metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+var (
+	totalCertificatesDesc = prometheus.NewDesc(
+		"zkcertificate_certificates_total",
+		"Total number of certificates.",
+		nil, nil,
+	)
+	expiredCertificatesDesc = prometheus.NewDesc(
+		"zkcertificate_certificates_expired",
+		"Number of expired certificates.",
+		nil, nil,
+	)
+	revokedCertificatesDesc = prometheus.NewDesc(
+		"zkcertificate_certificates_revoked",
+		"Number of revoked certificates.",
+		nil, nil,
+	)
+	certificatesByStandardDesc = prometheus.NewDesc(
+		"zkcertificate_certificates_by_standard",
+		"Number of certificates per zkCert standard.",
+		[]string{"standard"}, nil,
+	)
+	oldestExpirationDateDesc = prometheus.NewDesc(
+		"zkcertificate_oldest_expiration_timestamp_seconds",
+		"Unix timestamp of the earliest certificate expiration date.",
+		nil, nil,
+	)
+)
+
+// Collector is a prometheus.Collector that exposes zkcertificate.CertificateMetrics as gauges.
+type Collector struct {
+	collect func() zkcertificate.CertificateMetrics
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector creates a Collector that calls collect on every scrape.
+// The collect function is typically a closure around zkcertificate.CollectMetrics.
+func NewCollector(collect func() zkcertificate.CertificateMetrics) *Collector {
+	return &Collector{collect: collect}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- totalCertificatesDesc
+	ch <- expiredCertificatesDesc
+	ch <- revokedCertificatesDesc
+	ch <- certificatesByStandardDesc
+	ch <- oldestExpirationDateDesc
+}
+
+// Collect implements prometheus.Collector.
+// The oldest expiration date gauge is omitted if there are no certificates.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	metrics := c.collect()
+
+	ch <- prometheus.MustNewConstMetric(totalCertificatesDesc, prometheus.GaugeValue, float64(metrics.TotalCertificates))
+	ch <- prometheus.MustNewConstMetric(expiredCertificatesDesc, prometheus.GaugeValue, float64(metrics.ExpiredCertificates))
+	ch <- prometheus.MustNewConstMetric(revokedCertificatesDesc, prometheus.GaugeValue, float64(metrics.RevokedCertificates))
+
+	for standard, count := range metrics.StandardBreakdown {
+		ch <- prometheus.MustNewConstMetric(certificatesByStandardDesc, prometheus.GaugeValue, float64(count), string(standard))
+	}
+
+	if !metrics.OldestExpirationDate.IsZero() {
+		ch <- prometheus.MustNewConstMetric(oldestExpirationDateDesc, prometheus.GaugeValue, float64(metrics.OldestExpirationDate.Unix()))
+	}
+}
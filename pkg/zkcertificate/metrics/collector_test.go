@@ -0,0 +1,62 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate/metrics"
+)
+
+func TestCollector(t *testing.T) {
+	collector := metrics.NewCollector(func() zkcertificate.CertificateMetrics {
+		return zkcertificate.CertificateMetrics{
+			TotalCertificates:   3,
+			ExpiredCertificates: 1,
+			StandardBreakdown: map[zkcertificate.Standard]int{
+				zkcertificate.StandardKYC:        1,
+				zkcertificate.StandardSimpleJSON: 2,
+			},
+			OldestExpirationDate: time.Unix(1600000000, 0),
+		}
+	})
+
+	expected := `
+# HELP zkcertificate_certificates_by_standard Number of certificates per zkCert standard.
+# TYPE zkcertificate_certificates_by_standard gauge
+zkcertificate_certificates_by_standard{standard="gip1"} 1
+zkcertificate_certificates_by_standard{standard="gip2"} 2
+# HELP zkcertificate_certificates_expired Number of expired certificates.
+# TYPE zkcertificate_certificates_expired gauge
+zkcertificate_certificates_expired 1
+# HELP zkcertificate_certificates_revoked Number of revoked certificates.
+# TYPE zkcertificate_certificates_revoked gauge
+zkcertificate_certificates_revoked 0
+# HELP zkcertificate_certificates_total Total number of certificates.
+# TYPE zkcertificate_certificates_total gauge
+zkcertificate_certificates_total 3
+# HELP zkcertificate_oldest_expiration_timestamp_seconds Unix timestamp of the earliest certificate expiration date.
+# TYPE zkcertificate_oldest_expiration_timestamp_seconds gauge
+zkcertificate_oldest_expiration_timestamp_seconds 1.6e+09
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
@@ -0,0 +1,75 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/poseidon"
+)
+
+// poseidonHashNArity is the maximum amount of inputs hashed by a single Poseidon call in PoseidonHashN.
+const poseidonHashNArity = 6
+
+// PoseidonHashN computes a Poseidon hash of an arbitrary amount of inputs.
+//
+// The inputs are hashed as a tree with the following algorithm:
+//  1. The inputs are split into consecutive groups of 6 elements,
+//     where only the last group may contain fewer elements.
+//  2. Each group is hashed with a single Poseidon call prefixed with the domain tag n*2^8 + level,
+//     where n is the total amount of inputs and level is 0 for the inputs and increases by one on every step.
+//  3. If there is more than one group hash, the algorithm is applied again to the list of group hashes.
+//
+// For example, 14 inputs are hashed as
+//
+//	Poseidon(t1, Poseidon(t0, x1..x6), Poseidon(t0, x7..x12), Poseidon(t0, x13, x14))
+//
+// where tl = 14*2^8 + l.
+//
+// The domain tag prevents collisions between inputs of different lengths,
+// e.g. between x1..x7 and the two group hashes of x1..x7.
+func PoseidonHashN(inputs []*big.Int) (*big.Int, error) {
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("no inputs to hash")
+	}
+
+	return poseidonHashNLevel(inputs, len(inputs), 0)
+}
+
+func poseidonHashNLevel(inputs []*big.Int, n int, level int) (*big.Int, error) {
+	tag := new(big.Int).Lsh(big.NewInt(int64(n)), 8)
+	tag.Add(tag, big.NewInt(int64(level)))
+
+	groupHashes := make([]*big.Int, 0, (len(inputs)+poseidonHashNArity-1)/poseidonHashNArity)
+
+	for start := 0; start < len(inputs); start += poseidonHashNArity {
+		end := min(start+poseidonHashNArity, len(inputs))
+
+		hash, err := poseidon.Hash(append([]*big.Int{tag}, inputs[start:end]...))
+		if err != nil {
+			return nil, fmt.Errorf("hash inputs %d-%d of level %d: %w", start, end-1, level, err)
+		}
+
+		groupHashes = append(groupHashes, hash)
+	}
+
+	if len(groupHashes) == 1 {
+		return groupHashes[0], nil
+	}
+
+	return poseidonHashNLevel(groupHashes, n, level+1)
+}
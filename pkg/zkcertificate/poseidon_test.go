@@ -0,0 +1,102 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/iden3/go-iden3-crypto/poseidon"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestPoseidonHashN(t *testing.T) {
+	inputs := makeBigInts(14)
+
+	hash, err := zkcertificate.PoseidonHashN(inputs)
+	require.NoError(t, err)
+
+	tag0, tag1 := big.NewInt(14<<8), big.NewInt(14<<8+1)
+
+	first, err := poseidon.Hash(append([]*big.Int{tag0}, inputs[0:6]...))
+	require.NoError(t, err)
+	second, err := poseidon.Hash(append([]*big.Int{tag0}, inputs[6:12]...))
+	require.NoError(t, err)
+	third, err := poseidon.Hash(append([]*big.Int{tag0}, inputs[12:14]...))
+	require.NoError(t, err)
+	expected, err := poseidon.Hash([]*big.Int{tag1, first, second, third})
+	require.NoError(t, err)
+
+	require.Equal(t, expected, hash)
+}
+
+func TestPoseidonHashN_small(t *testing.T) {
+	inputs := makeBigInts(6)
+
+	hash, err := zkcertificate.PoseidonHashN(inputs)
+	require.NoError(t, err)
+
+	expected, err := poseidon.Hash(append([]*big.Int{big.NewInt(6 << 8)}, inputs...))
+	require.NoError(t, err)
+	require.Equal(t, expected, hash)
+}
+
+func TestPoseidonHashN_lengthCollision(t *testing.T) {
+	inputs := makeBigInts(7)
+
+	hash, err := zkcertificate.PoseidonHashN(inputs)
+	require.NoError(t, err)
+
+	tag := big.NewInt(7 << 8)
+	first, err := poseidon.Hash(append([]*big.Int{tag}, inputs[0:6]...))
+	require.NoError(t, err)
+	second, err := poseidon.Hash([]*big.Int{tag, inputs[6]})
+	require.NoError(t, err)
+
+	groupHash, err := zkcertificate.PoseidonHashN([]*big.Int{first, second})
+	require.NoError(t, err)
+	require.NotEqual(t, hash, groupHash)
+
+	plainFirst, err := poseidon.Hash(inputs[0:6])
+	require.NoError(t, err)
+	plainSecond, err := poseidon.Hash(inputs[6:7])
+	require.NoError(t, err)
+
+	plainGroupHash, err := zkcertificate.PoseidonHashN([]*big.Int{plainFirst, plainSecond})
+	require.NoError(t, err)
+	require.NotEqual(t, hash, plainGroupHash)
+}
+
+func TestPoseidonHashN_large(t *testing.T) {
+	_, err := zkcertificate.PoseidonHashN(makeBigInts(100))
+	require.NoError(t, err)
+}
+
+func TestPoseidonHashN_empty(t *testing.T) {
+	_, err := zkcertificate.PoseidonHashN(nil)
+	require.Error(t, err)
+}
+
+func makeBigInts(amount int) []*big.Int {
+	res := make([]*big.Int, amount)
+	for i := range res {
+		res[i] = big.NewInt(int64(i + 1))
+	}
+
+	return res
+}
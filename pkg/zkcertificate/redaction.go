@@ -0,0 +1,380 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/iden3/go-iden3-crypto/poseidon"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
+)
+
+// FieldsCommitment commits to the top-level content fields of a certificate for selective disclosure.
+// Every field is committed as Poseidon(HashBytes(name), HashBytes(value), blinding) into a Merkle tree of fields,
+// ordered by field name. The random blinding prevents guessing hidden values from their hashes.
+// The blindings are secret to the holder, only the root is public as part of the leaf hash.
+type FieldsCommitment struct {
+	Root      Hash            `json:"root"`
+	Blindings map[string]Hash `json:"blindings"`
+}
+
+func (f *FieldsCommitment) clone() *FieldsCommitment {
+	if f == nil {
+		return nil
+	}
+
+	blindings := make(map[string]Hash, len(f.Blindings))
+	for name, blinding := range f.Blindings {
+		blindings[name] = cloneHash(blinding)
+	}
+
+	return &FieldsCommitment{
+		Root:      cloneHash(f.Root),
+		Blindings: blindings,
+	}
+}
+
+// RedactedCertificate represents a certificate with selectively disclosed content.
+// Revealed fields include their values and blindings, while hidden fields are represented only by their hashes.
+// The fields root is bound to the leaf hash in the DID, which also covers the provider signature.
+type RedactedCertificate[T any] struct {
+	DID               string          `json:"did"`
+	Standard          Standard        `json:"zkCertStandard"`
+	HolderCommitment  Hash            `json:"holderCommitment"`
+	ContentHash       Hash            `json:"contentHash"`
+	ExpirationDate    Timestamp       `json:"expirationDate"`
+	Provider          ProviderData    `json:"providerData"`
+	RandomSalt        int64           `json:"randomSalt"`
+	LinkedPreviousDID string          `json:"linkedPreviousDid,omitempty"`
+	FieldsRoot        Hash            `json:"fieldsRoot"`
+	SaltedContent     bool            `json:"saltedContent,omitempty"`
+	RevealedFields    []RevealedField `json:"revealedFields"`
+	HiddenFields      []HiddenField   `json:"hiddenFields"`
+}
+
+// RevealedField represents a disclosed content field together with a proof of its membership in the fields tree.
+type RevealedField struct {
+	Name     string          `json:"name"`
+	Value    json.RawMessage `json:"value"`
+	Blinding Hash            `json:"blinding"`
+	Proof    merkle.Proof    `json:"proof"`
+}
+
+// HiddenField represents an undisclosed content field by its hash and a proof of its membership in the fields tree.
+type HiddenField struct {
+	Hash  Hash         `json:"hash"`
+	Proof merkle.Proof `json:"proof"`
+}
+
+// WithFieldsCommitment returns a copy of the certificate committing to its content fields with fresh random
+// blindings, which is required by Redact. The fields root is included into the leaf hash, so the commitment
+// must be created before the certificate is registered.
+func (c *Certificate[T]) WithFieldsCommitment() (*Certificate[T], error) {
+	certificate := c.Clone()
+
+	commitment, err := newFieldsCommitment(certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	certificate.FieldsCommitment = commitment
+
+	if err := certificate.updateLeafHash(); err != nil {
+		return nil, err
+	}
+
+	return certificate, nil
+}
+
+// Redact produces a redacted copy of the certificate revealing only the given top-level content fields.
+// Elements of array contents are addressed by their decimal index.
+// The certificate must have a fields commitment, see WithFieldsCommitment.
+func (c *Certificate[T]) Redact(revealedFields []string) (*RedactedCertificate[T], error) {
+	if c.FieldsCommitment == nil {
+		return nil, fmt.Errorf("certificate has no fields commitment")
+	}
+
+	fields, err := c.contentFields()
+	if err != nil {
+		return nil, err
+	}
+
+	revealed := make(map[string]bool, len(revealedFields))
+	for _, name := range revealedFields {
+		if _, ok := fields[name]; !ok {
+			return nil, fmt.Errorf("unknown content field %q", name)
+		}
+
+		revealed[name] = true
+	}
+
+	tree, names, fieldHashes, err := buildFieldsTree(fields, c.FieldsCommitment.Blindings)
+	if err != nil {
+		return nil, err
+	}
+
+	if root := HashFromBigInt(tree.Root().Value.ToBig()); root.Cmp(c.FieldsCommitment.Root) != 0 {
+		return nil, fmt.Errorf("fields commitment does not match certificate content")
+	}
+
+	redacted := &RedactedCertificate[T]{
+		DID:               c.DID,
+		Standard:          c.Standard,
+		HolderCommitment:  c.HolderCommitment,
+		ContentHash:       c.ContentHash,
+		ExpirationDate:    c.ExpirationDate,
+		Provider:          c.Provider.clone(),
+		RandomSalt:        c.RandomSalt,
+		LinkedPreviousDID: c.LinkedPreviousDID,
+		FieldsRoot:        c.FieldsCommitment.Root,
+		SaltedContent:     c.SaltedContent,
+	}
+
+	for i, name := range names {
+		proof, err := tree.GetProof(i)
+		if err != nil {
+			return nil, fmt.Errorf("get proof of content field %q: %w", name, err)
+		}
+
+		if revealed[name] {
+			redacted.RevealedFields = append(redacted.RevealedFields, RevealedField{
+				Name:     name,
+				Value:    fields[name],
+				Blinding: c.FieldsCommitment.Blindings[name],
+				Proof:    proof,
+			})
+		} else {
+			redacted.HiddenFields = append(redacted.HiddenFields, HiddenField{
+				Hash:  fieldHashes[i],
+				Proof: proof,
+			})
+		}
+	}
+
+	return redacted, nil
+}
+
+// Verify checks that the fields root, together with the other certificate fields, produces the leaf hash of
+// the DID, that the provider signature is valid, and that every revealed and hidden field belongs to the fields
+// tree. Callers must additionally check that the leaf hash is registered, e.g. with IssuedCertificate.OnChainStatus.
+func (r *RedactedCertificate[T]) Verify() error {
+	opts := []LeafHashOption{WithFieldsRoot(r.FieldsRoot)}
+	if r.SaltedContent {
+		opts = append(opts, WithSaltedContent())
+	}
+	if r.LinkedPreviousDID != "" {
+		opts = append(opts, withPreviousDID(r.LinkedPreviousDID))
+	}
+
+	leafHash, err := LeafHash(
+		r.ContentHash,
+		&r.Provider.PublicKey,
+		&r.Provider.Signature,
+		r.HolderCommitment,
+		r.RandomSalt,
+		time.Time(r.ExpirationDate),
+		opts...,
+	)
+	if err != nil {
+		return fmt.Errorf("compute leaf hash: %w", err)
+	}
+
+	if DID(r.Standard, leafHash) != r.DID {
+		return fmt.Errorf("fields root does not match certificate did")
+	}
+
+	signatureValid, err := VerifySignature(&r.Provider.PublicKey, r.ContentHash, r.HolderCommitment, &r.Provider.Signature)
+	if err != nil {
+		return fmt.Errorf("verify signature: %w", err)
+	}
+	if !signatureValid {
+		return fmt.Errorf("invalid signature")
+	}
+
+	for _, field := range r.RevealedFields {
+		fieldHash, err := contentFieldHash(field.Name, field.Value, field.Blinding)
+		if err != nil {
+			return fmt.Errorf("hash content field %q: %w", field.Name, err)
+		}
+
+		if err := verifyMerkleProof(fieldHash, field.Proof, r.FieldsRoot); err != nil {
+			return fmt.Errorf("verify content field %q: %w", field.Name, err)
+		}
+	}
+
+	for i, field := range r.HiddenFields {
+		if err := verifyMerkleProof(field.Hash, field.Proof, r.FieldsRoot); err != nil {
+			return fmt.Errorf("verify hidden field %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// newFieldsCommitment commits to the content fields of the certificate with fresh random blindings.
+func newFieldsCommitment[T any](c *Certificate[T]) (*FieldsCommitment, error) {
+	fields, err := c.contentFields()
+	if err != nil {
+		return nil, err
+	}
+
+	blindings := make(map[string]Hash, len(fields))
+	for name := range fields {
+		blinding, err := randomFieldElement()
+		if err != nil {
+			return nil, fmt.Errorf("generate blinding: %w", err)
+		}
+
+		blindings[name] = HashFromBigInt(blinding)
+	}
+
+	tree, _, _, err := buildFieldsTree(fields, blindings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FieldsCommitment{
+		Root:      HashFromBigInt(tree.Root().Value.ToBig()),
+		Blindings: blindings,
+	}, nil
+}
+
+// buildFieldsTree builds the Merkle tree of the blinded content field hashes, ordered by field name.
+func buildFieldsTree(
+	fields map[string]json.RawMessage,
+	blindings map[string]Hash,
+) (*merkle.Tree, []string, []Hash, error) {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tree, err := merkle.NewEmptyTree(bits.Len(uint(len(names)-1)), merkle.EmptyLeafValue)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("create fields tree: %w", err)
+	}
+
+	fieldHashes := make([]Hash, len(names))
+	for i, name := range names {
+		blinding, ok := blindings[name]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("no blinding for content field %q", name)
+		}
+
+		fieldHashes[i], err = contentFieldHash(name, fields[name], blinding)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("hash content field %q: %w", name, err)
+		}
+
+		if err := tree.SetLeaf(i, merkle.TreeNode{Value: uint256.MustFromBig(fieldHashes[i].BigInt())}); err != nil {
+			return nil, nil, nil, fmt.Errorf("set fields tree leaf: %w", err)
+		}
+	}
+
+	return tree, names, fieldHashes, nil
+}
+
+func (c *Certificate[T]) contentFields() (map[string]json.RawMessage, error) {
+	data, err := c.ContentJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err == nil {
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("certificate content has no fields")
+		}
+
+		return fields, nil
+	}
+
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return nil, fmt.Errorf("certificate content is neither a json object nor an array")
+	}
+
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("certificate content has no fields")
+	}
+
+	fields = make(map[string]json.RawMessage, len(elements))
+	for i, element := range elements {
+		fields[strconv.Itoa(i)] = element
+	}
+
+	return fields, nil
+}
+
+func contentFieldHash(name string, value json.RawMessage, blinding Hash) (Hash, error) {
+	nameHash, err := poseidon.HashBytes([]byte(name))
+	if err != nil {
+		return Hash{}, fmt.Errorf("hash name: %w", err)
+	}
+
+	valueHash, err := poseidon.HashBytes(value)
+	if err != nil {
+		return Hash{}, fmt.Errorf("hash value: %w", err)
+	}
+
+	hash, err := poseidon.Hash([]*big.Int{nameHash, valueHash, blinding.BigInt()})
+	if err != nil {
+		return Hash{}, fmt.Errorf("compute hash: %w", err)
+	}
+
+	return HashFromBigInt(hash), nil
+}
+
+// verifyMerkleProof checks that the proof authenticates the leaf hash against the root
+// of a Merkle tree hashed with merkle.HashFunc.
+func verifyMerkleProof(leafHash Hash, proof merkle.Proof, root Hash) error {
+	if proof.Leaf.Value == nil || proof.Leaf.Value.ToBig().Cmp(leafHash.BigInt()) != 0 {
+		return fmt.Errorf("proof leaf does not match leaf hash")
+	}
+
+	node := leafHash.BigInt()
+	for level, sibling := range proof.Path {
+		if sibling.Value == nil {
+			return fmt.Errorf("proof node %d is empty", level)
+		}
+
+		inputs := []*big.Int{node, sibling.Value.ToBig()}
+		if proof.LeafIndex>>level&1 == 1 {
+			inputs[0], inputs[1] = inputs[1], inputs[0]
+		}
+
+		var err error
+		node, err = merkle.HashFunc(inputs)
+		if err != nil {
+			return fmt.Errorf("compute hash: %w", err)
+		}
+	}
+
+	if node.Cmp(root.BigInt()) != 0 {
+		return fmt.Errorf("proof does not match root")
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func makeCommittedCertificate(t *testing.T) *zkcertificate.Certificate[zkcertificate.SimpleJSONContent] {
+	t.Helper()
+
+	certificate, _ := makeCertificate(t)
+
+	committed, err := certificate.WithFieldsCommitment()
+	require.NoError(t, err)
+
+	return committed
+}
+
+func TestCertificate_WithFieldsCommitment(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	committed, err := certificate.WithFieldsCommitment()
+	require.NoError(t, err)
+	require.Nil(t, certificate.FieldsCommitment)
+	require.NotNil(t, committed.FieldsCommitment)
+	require.Len(t, committed.FieldsCommitment.Blindings, 2)
+	require.NotEqual(t, certificate.LeafHash, committed.LeafHash)
+	require.NoError(t, zkcertificate.VerifyChain([]*zkcertificate.Certificate[zkcertificate.SimpleJSONContent]{committed}))
+
+	recommitted, err := certificate.WithFieldsCommitment()
+	require.NoError(t, err)
+	require.NotEqual(t, committed.FieldsCommitment.Root, recommitted.FieldsCommitment.Root)
+}
+
+func TestCertificate_Redact(t *testing.T) {
+	certificate := makeCommittedCertificate(t)
+
+	redacted, err := certificate.Redact([]string{"1"})
+	require.NoError(t, err)
+	require.Equal(t, certificate.DID, redacted.DID)
+	require.Equal(t, certificate.ContentHash, redacted.ContentHash)
+
+	require.Len(t, redacted.RevealedFields, 1)
+	require.Equal(t, "1", redacted.RevealedFields[0].Name)
+	require.Equal(t, 1, redacted.RevealedFields[0].Proof.LeafIndex)
+
+	revealedValue, err := json.Marshal(certificate.Content[1])
+	require.NoError(t, err)
+	require.JSONEq(t, string(revealedValue), string(redacted.RevealedFields[0].Value))
+
+	require.Len(t, redacted.HiddenFields, 1)
+	require.Equal(t, 0, redacted.HiddenFields[0].Proof.LeafIndex)
+
+	require.NoError(t, redacted.Verify())
+}
+
+func TestCertificate_Redact_unknownField(t *testing.T) {
+	certificate := makeCommittedCertificate(t)
+
+	_, err := certificate.Redact([]string{"name"})
+	require.Error(t, err)
+}
+
+func TestCertificate_Redact_noFieldsCommitment(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	_, err := certificate.Redact([]string{"1"})
+	require.Error(t, err)
+}
+
+func TestRedactedCertificate_Verify_tampered(t *testing.T) {
+	certificate := makeCommittedCertificate(t)
+	forged := makeCommittedCertificate(t)
+
+	tests := []struct {
+		name   string
+		tamper func(redacted *zkcertificate.RedactedCertificate[zkcertificate.SimpleJSONContent])
+	}{
+		{
+			name: "revealed value",
+			tamper: func(redacted *zkcertificate.RedactedCertificate[zkcertificate.SimpleJSONContent]) {
+				redacted.RevealedFields[0].Value = json.RawMessage(`"1"`)
+			},
+		},
+		{
+			name: "hidden hash",
+			tamper: func(redacted *zkcertificate.RedactedCertificate[zkcertificate.SimpleJSONContent]) {
+				redacted.HiddenFields[0].Hash = redacted.FieldsRoot
+			},
+		},
+		{
+			name: "revealed blinding",
+			tamper: func(redacted *zkcertificate.RedactedCertificate[zkcertificate.SimpleJSONContent]) {
+				redacted.RevealedFields[0].Blinding = redacted.HiddenFields[0].Hash
+			},
+		},
+		{
+			name: "fields root of another certificate",
+			tamper: func(redacted *zkcertificate.RedactedCertificate[zkcertificate.SimpleJSONContent]) {
+				other, err := forged.Redact([]string{"0", "1"})
+				require.NoError(t, err)
+
+				redacted.FieldsRoot = other.FieldsRoot
+				redacted.RevealedFields = other.RevealedFields[1:]
+				redacted.HiddenFields = []zkcertificate.HiddenField{{
+					Hash:  redacted.HiddenFields[0].Hash,
+					Proof: other.RevealedFields[0].Proof,
+				}}
+			},
+		},
+		{
+			name: "provider signature",
+			tamper: func(redacted *zkcertificate.RedactedCertificate[zkcertificate.SimpleJSONContent]) {
+				redacted.Provider = forged.Provider
+			},
+		},
+		{
+			name: "revealed field name",
+			tamper: func(redacted *zkcertificate.RedactedCertificate[zkcertificate.SimpleJSONContent]) {
+				redacted.RevealedFields[0].Name = "0"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redacted, err := certificate.Redact([]string{"1"})
+			require.NoError(t, err)
+
+			tt.tamper(redacted)
+			require.Error(t, redacted.Verify())
+		})
+	}
+}
+
+func TestCertificate_WithContent_fieldsCommitment(t *testing.T) {
+	certificate, privateKey := makeCertificate(t)
+
+	committed, err := certificate.WithFieldsCommitment()
+	require.NoError(t, err)
+
+	newContent, err := zkcertificate.SimpleJSON{"name": "Jane Doe", "age": "31"}.FFEncode()
+	require.NoError(t, err)
+
+	updated, err := committed.WithContent(newContent, privateKey, 2)
+	require.NoError(t, err)
+	require.NotNil(t, updated.FieldsCommitment)
+	require.NotEqual(t, committed.FieldsCommitment.Root, updated.FieldsCommitment.Root)
+
+	redacted, err := updated.Redact([]string{"0"})
+	require.NoError(t, err)
+	require.NoError(t, redacted.Verify())
+}
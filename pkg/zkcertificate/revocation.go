@@ -0,0 +1,109 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Revocation represents a record about revocation of a certificate identified by its leaf hash.
+type Revocation struct {
+	LeafHash  Hash
+	RevokedAt time.Time
+	Reason    string
+}
+
+type revocationDTO struct {
+	LeafHash  Hash      `json:"leafHash"`
+	RevokedAt Timestamp `json:"revokedAt"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (r Revocation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(revocationDTO{
+		LeafHash:  r.LeafHash,
+		RevokedAt: Timestamp(r.RevokedAt),
+		Reason:    r.Reason,
+	})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (r *Revocation) UnmarshalJSON(data []byte) error {
+	var dto revocationDTO
+	if err := json.Unmarshal(data, &dto); err != nil {
+		return err
+	}
+
+	*r = Revocation{
+		LeafHash:  dto.LeafHash,
+		RevokedAt: time.Time(dto.RevokedAt),
+		Reason:    dto.Reason,
+	}
+	return nil
+}
+
+// IsRevoked returns true if the certificate leaf hash appears in the given list of revocations.
+func IsRevoked[T any](cert Certificate[T], revocations []Revocation) bool {
+	for _, revocation := range revocations {
+		if revocation.LeafHash.Cmp(cert.LeafHash) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RevocationSet is a collection of revocations indexed by the revoked certificate leaf hash.
+// Leaf hashes are indexed by their decimal representation, so hashes outside of the field are kept apart.
+// The zero value is an empty set ready to use.
+type RevocationSet struct {
+	revocations map[string]Revocation
+}
+
+// NewRevocationSet creates a new RevocationSet containing the given revocations.
+func NewRevocationSet(revocations ...Revocation) *RevocationSet {
+	set := &RevocationSet{
+		revocations: make(map[string]Revocation, len(revocations)),
+	}
+
+	for _, revocation := range revocations {
+		set.Add(revocation)
+	}
+
+	return set
+}
+
+// Add adds the revocation to the set, replacing any previous revocation of the same leaf hash.
+func (s *RevocationSet) Add(revocation Revocation) {
+	if s.revocations == nil {
+		s.revocations = make(map[string]Revocation)
+	}
+
+	s.revocations[revocation.LeafHash.String()] = revocation
+}
+
+// Remove removes the revocation of the given leaf hash from the set.
+func (s *RevocationSet) Remove(leafHash Hash) {
+	delete(s.revocations, leafHash.String())
+}
+
+// Contains returns true if the set contains a revocation of the given leaf hash.
+func (s *RevocationSet) Contains(leafHash Hash) bool {
+	_, ok := s.revocations[leafHash.String()]
+	return ok
+}
@@ -0,0 +1,92 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestIsRevoked(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+
+	revocations := []zkcertificate.Revocation{
+		{LeafHash: zkcertificate.HashFromBigInt(big.NewInt(1))},
+	}
+	require.False(t, zkcertificate.IsRevoked(*certificate, revocations))
+
+	revocations = append(revocations, zkcertificate.Revocation{LeafHash: certificate.LeafHash})
+	require.True(t, zkcertificate.IsRevoked(*certificate, revocations))
+}
+
+func TestRevocation_JSON(t *testing.T) {
+	revocation := zkcertificate.Revocation{
+		LeafHash:  zkcertificate.HashFromBigInt(big.NewInt(192021)),
+		RevokedAt: time.Unix(1700000000, 0).UTC(),
+		Reason:    "fraud",
+	}
+
+	data, err := json.Marshal(revocation)
+	require.NoError(t, err)
+	require.Equal(t, []byte(`{"leafHash":"192021","revokedAt":1700000000,"reason":"fraud"}`), data)
+
+	var deserialized zkcertificate.Revocation
+	require.NoError(t, json.Unmarshal(data, &deserialized))
+	require.Equal(t, revocation, deserialized)
+}
+
+func TestRevocationSet(t *testing.T) {
+	leafHash := zkcertificate.HashFromBigInt(big.NewInt(222324))
+
+	set := zkcertificate.NewRevocationSet()
+	require.False(t, set.Contains(leafHash))
+
+	set.Add(zkcertificate.Revocation{LeafHash: leafHash})
+	require.True(t, set.Contains(zkcertificate.HashFromBigInt(big.NewInt(222324))))
+
+	set.Remove(leafHash)
+	require.False(t, set.Contains(leafHash))
+}
+
+func TestRevocationSet_zeroValue(t *testing.T) {
+	leafHash := zkcertificate.HashFromBigInt(big.NewInt(222324))
+
+	var set zkcertificate.RevocationSet
+	require.False(t, set.Contains(leafHash))
+
+	set.Remove(leafHash)
+
+	set.Add(zkcertificate.Revocation{LeafHash: leafHash})
+	require.True(t, set.Contains(leafHash))
+}
+
+func TestRevocationSet_outOfField(t *testing.T) {
+	leafHash := zkcertificate.HashFromBigInt(big.NewInt(222324))
+	negated := zkcertificate.HashFromBigInt(big.NewInt(-222324))
+	large := zkcertificate.HashFromBigInt(new(big.Int).Lsh(big.NewInt(1), 300))
+
+	set := zkcertificate.NewRevocationSet(zkcertificate.Revocation{LeafHash: large})
+	require.True(t, set.Contains(large))
+
+	set.Add(zkcertificate.Revocation{LeafHash: leafHash})
+	require.False(t, set.Contains(negated))
+}
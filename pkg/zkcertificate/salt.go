@@ -0,0 +1,40 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// NewSaltFromRandom generates a cryptographically secure random salt in range [1, MaxInt64].
+// Only the lower 63 bits of the random value are used, so the salt is never negative.
+func NewSaltFromRandom() (int64, error) {
+	var buf [8]byte
+
+	for {
+		if _, err := io.ReadFull(rand.Reader, buf[:]); err != nil {
+			return 0, fmt.Errorf("read random bytes: %w", err)
+		}
+
+		if salt := int64(binary.BigEndian.Uint64(buf[:]) & math.MaxInt64); salt != 0 {
+			return salt, nil
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestNewSaltFromRandom(t *testing.T) {
+	salts := make(map[int64]struct{})
+
+	for i := 0; i < 100; i++ {
+		salt, err := zkcertificate.NewSaltFromRandom()
+		require.NoError(t, err)
+		require.Positive(t, salt)
+
+		salts[salt] = struct{}{}
+	}
+
+	require.Len(t, salts, 100)
+}
@@ -0,0 +1,120 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/iden3/go-iden3-crypto/poseidon"
+	"github.com/iden3/go-iden3-crypto/utils"
+)
+
+// SignatureToBytes encodes the signature into a 96-byte array consisting of
+// R8.X, R8.Y and S, each encoded as a 32-byte little-endian integer.
+func SignatureToBytes(sig *babyjub.Signature) ([96]byte, error) {
+	var res [96]byte
+
+	if sig == nil || sig.R8 == nil || sig.R8.X == nil || sig.R8.Y == nil || sig.S == nil {
+		return res, fmt.Errorf("incomplete signature")
+	}
+
+	if !utils.CheckBigIntInField(sig.R8.X) || !utils.CheckBigIntInField(sig.R8.Y) {
+		return res, fmt.Errorf("signature r8 point is not in the field")
+	}
+
+	if sig.S.Sign() < 0 || sig.S.Cmp(babyjub.SubOrder) >= 0 {
+		return res, fmt.Errorf("invalid s component of signature")
+	}
+
+	r8x := utils.BigIntLEBytes(sig.R8.X)
+	r8y := utils.BigIntLEBytes(sig.R8.Y)
+	s := utils.BigIntLEBytes(sig.S)
+
+	copy(res[0:32], r8x[:])
+	copy(res[32:64], r8y[:])
+	copy(res[64:96], s[:])
+
+	return res, nil
+}
+
+// SignatureFromBytes decodes a signature encoded with SignatureToBytes.
+// It validates that the R8 point is on the Baby Jubjub curve.
+func SignatureFromBytes(b [96]byte) (*babyjub.Signature, error) {
+	r8 := &babyjub.Point{
+		X: utils.SetBigIntFromLEBytes(new(big.Int), b[0:32]),
+		Y: utils.SetBigIntFromLEBytes(new(big.Int), b[32:64]),
+	}
+
+	if !utils.CheckBigIntInField(r8.X) || !utils.CheckBigIntInField(r8.Y) {
+		return nil, fmt.Errorf("signature r8 point is not in the field")
+	}
+
+	if !r8.InCurve() {
+		return nil, fmt.Errorf("signature r8 point is not on the curve")
+	}
+
+	s := utils.SetBigIntFromLEBytes(new(big.Int), b[64:96])
+	if s.Cmp(babyjub.SubOrder) >= 0 {
+		return nil, fmt.Errorf("invalid s component of signature")
+	}
+
+	return &babyjub.Signature{R8: r8, S: s}, nil
+}
+
+// SignedItem holds a certificate signature together with the hashes it signs.
+type SignedItem struct {
+	ContentHash    Hash
+	CommitmentHash Hash
+	Signature      *babyjub.Signature
+}
+
+// VerifySignatureBatch verifies the signatures of all items against the same provider key.
+// The Poseidon messages of all items are computed before any signature is verified,
+// so a hash error or an incomplete signature of any item is reported together with its index
+// and no results are returned.
+// The result at index i reports whether the signature of items[i] is valid, a nil signature is invalid.
+func VerifySignatureBatch(key *babyjub.PublicKey, items []SignedItem) ([]bool, error) {
+	if key == nil {
+		return nil, fmt.Errorf("provider key is nil")
+	}
+
+	messages := make([]*big.Int, len(items))
+	for i, item := range items {
+		if sig := item.Signature; sig != nil && (sig.R8 == nil || sig.R8.X == nil || sig.R8.Y == nil || sig.S == nil) {
+			return nil, fmt.Errorf("signature of item %d is incomplete", i)
+		}
+
+		message, err := poseidon.Hash([]*big.Int{item.ContentHash.BigInt(), item.CommitmentHash.BigInt()})
+		if err != nil {
+			return nil, fmt.Errorf("hash message of item %d: %w", i, err)
+		}
+
+		messages[i] = message
+	}
+
+	results := make([]bool, len(items))
+	for i, item := range items {
+		if item.Signature == nil {
+			continue
+		}
+
+		results[i] = key.VerifyPoseidon(messages[i], item.Signature)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,105 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"testing"
+
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestSignatureBytes(t *testing.T) {
+	certificate, _ := makeCertificate(t)
+	signature := certificate.Provider.Signature
+
+	data, err := zkcertificate.SignatureToBytes(&signature)
+	require.NoError(t, err)
+
+	decoded, err := zkcertificate.SignatureFromBytes(data)
+	require.NoError(t, err)
+	require.Equal(t, signature.R8.X, decoded.R8.X)
+	require.Equal(t, signature.R8.Y, decoded.R8.Y)
+	require.Equal(t, signature.S, decoded.S)
+
+	isValid, err := zkcertificate.VerifySignature(
+		&certificate.Provider.PublicKey,
+		certificate.ContentHash,
+		certificate.HolderCommitment,
+		decoded,
+	)
+	require.NoError(t, err)
+	require.True(t, isValid)
+}
+
+func TestSignatureToBytes_incomplete(t *testing.T) {
+	_, err := zkcertificate.SignatureToBytes(&babyjub.Signature{})
+	require.Error(t, err)
+}
+
+func TestSignatureFromBytes_notOnCurve(t *testing.T) {
+	var data [96]byte
+	data[0] = 1
+	data[32] = 1
+
+	_, err := zkcertificate.SignatureFromBytes(data)
+	require.Error(t, err)
+}
+
+func TestVerifySignatureBatch(t *testing.T) {
+	certificate, privateKey := makeCertificate(t)
+	signature := certificate.Provider.Signature
+
+	otherSignature, err := zkcertificate.SignCertificate(
+		babyjub.NewRandPrivKey(),
+		certificate.ContentHash,
+		certificate.HolderCommitment,
+	)
+	require.NoError(t, err)
+
+	results, err := zkcertificate.VerifySignatureBatch(privateKey.Public(), []zkcertificate.SignedItem{
+		{ContentHash: certificate.ContentHash, CommitmentHash: certificate.HolderCommitment, Signature: &signature},
+		{ContentHash: certificate.ContentHash, CommitmentHash: certificate.HolderCommitment, Signature: otherSignature},
+		{ContentHash: certificate.ContentHash, CommitmentHash: certificate.HolderCommitment},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []bool{true, false, false}, results)
+}
+
+func TestVerifySignatureBatch_hashError(t *testing.T) {
+	certificate, privateKey := makeCertificate(t)
+	signature := certificate.Provider.Signature
+
+	_, err := zkcertificate.VerifySignatureBatch(privateKey.Public(), []zkcertificate.SignedItem{
+		{ContentHash: certificate.ContentHash, CommitmentHash: certificate.HolderCommitment, Signature: &signature},
+		{ContentHash: zkcertificate.HashFromBigInt(constants.Q), CommitmentHash: certificate.HolderCommitment, Signature: &signature},
+	})
+	require.ErrorContains(t, err, "item 1")
+}
+
+func TestVerifySignatureBatch_incompleteSignature(t *testing.T) {
+	certificate, privateKey := makeCertificate(t)
+	signature := certificate.Provider.Signature
+
+	_, err := zkcertificate.VerifySignatureBatch(privateKey.Public(), []zkcertificate.SignedItem{
+		{ContentHash: certificate.ContentHash, CommitmentHash: certificate.HolderCommitment, Signature: &signature},
+		{ContentHash: certificate.ContentHash, CommitmentHash: certificate.HolderCommitment, Signature: &babyjub.Signature{}},
+	})
+	require.ErrorContains(t, err, "item 1")
+}
@@ -16,8 +16,11 @@
 package zkcertificate
 
 import (
+	"errors"
 	"fmt"
 	"slices"
+	"strconv"
+	"strings"
 )
 
 // Standard represents a string that indicates the standard of Zero Knowledge certificates.
@@ -33,6 +36,54 @@ var allStandards = []string{
 	StandardSimpleJSON.String(),
 }
 
+// ErrUnknownStandard is returned when a value does not correspond to any known Standard.
+var ErrUnknownStandard = errors.New("unknown standard")
+
+// ErrNoVersionSuffix is returned when a Standard does not carry a version suffix.
+var ErrNoVersionSuffix = errors.New("standard has no version suffix")
+
+// ParseStandardVersion splits a versioned standard such as "gip69v2" into its canonical name "gip69"
+// and version 2. It returns an error wrapping ErrNoVersionSuffix if the standard does not end with
+// a "v" followed by a positive decimal version.
+func ParseStandardVersion(s Standard) (name string, version int, err error) {
+	i := strings.LastIndexByte(string(s), 'v')
+	if i <= 0 || i == len(s)-1 {
+		return "", 0, fmt.Errorf("%w: %q", ErrNoVersionSuffix, s)
+	}
+
+	suffix := string(s[i+1:])
+	if strings.TrimLeft(suffix, "0123456789") != "" || suffix[0] == '0' {
+		return "", 0, fmt.Errorf("%w: %q", ErrNoVersionSuffix, s)
+	}
+
+	version, err = strconv.Atoi(suffix)
+	if err != nil {
+		return "", 0, fmt.Errorf("parse version of standard %q: %w", s, err)
+	}
+
+	return string(s[:i]), version, nil
+}
+
+// standardVersion returns the version of the standard, or 0 if the standard is not versioned.
+func standardVersion(s Standard) int {
+	_, version, err := ParseStandardVersion(s)
+	if err != nil {
+		return 0
+	}
+
+	return version
+}
+
+// ParseStandard converts the given value to a Standard.
+// It returns an error wrapping ErrUnknownStandard if the value is not a valid Standard.
+func ParseStandard(s string) (Standard, error) {
+	if !IsStandard(s) {
+		return "", fmt.Errorf("%w %q", ErrUnknownStandard, s)
+	}
+
+	return Standard(s), nil
+}
+
 // IsStandard returns true if given value is a valid Standard.
 func IsStandard(value string) bool {
 	return slices.Contains(allStandards, value)
@@ -43,15 +94,31 @@ func (s Standard) String() string {
 	return string(s)
 }
 
+// Namespace returns the part of the standard before the first "/" or "-", e.g. "galactica" for "galactica/kyc".
+// It returns an empty string if the standard has no delimiter.
+func (s Standard) Namespace() string {
+	i := strings.IndexAny(string(s), "/-")
+	if i < 0 {
+		return ""
+	}
+
+	return string(s[:i])
+}
+
+// Name returns the part of the standard after the first "/" or "-", e.g. "kyc" for "galactica/kyc".
+// It returns the whole standard if the standard has no delimiter.
+func (s Standard) Name() string {
+	return string(s[strings.IndexAny(string(s), "/-")+1:])
+}
+
 // UnmarshalText implements [encoding.TextUnmarshaler].
 func (s *Standard) UnmarshalText(value []byte) error {
-	text := string(value)
-
-	if !IsStandard(text) {
-		return fmt.Errorf("invalid value")
+	standard, err := ParseStandard(string(value))
+	if err != nil {
+		return err
 	}
 
-	*s = Standard(value)
+	*s = standard
 	return nil
 }
 
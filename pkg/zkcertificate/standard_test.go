@@ -82,3 +82,91 @@ func TestStandard_UnmarshalText(t *testing.T) {
 		})
 	}
 }
+
+func TestParseStandard(t *testing.T) {
+	standard, err := zkcertificate.ParseStandard("gip2")
+	require.NoError(t, err)
+	require.Equal(t, zkcertificate.StandardSimpleJSON, standard)
+
+	_, err = zkcertificate.ParseStandard("gip")
+	require.ErrorIs(t, err, zkcertificate.ErrUnknownStandard)
+}
+
+func TestParseStandardVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		standard    zkcertificate.Standard
+		wantName    string
+		wantVersion int
+		wantErr     bool
+	}{
+		{
+			name:        "Versioned standard",
+			standard:    "gip69v2",
+			wantName:    "gip69",
+			wantVersion: 2,
+		},
+		{
+			name:        "Multi-digit version",
+			standard:    "gip69v12",
+			wantName:    "gip69",
+			wantVersion: 12,
+		},
+		{
+			name:     "Unversioned standard",
+			standard: zkcertificate.StandardKYC,
+			wantErr:  true,
+		},
+		{
+			name:     "Empty version",
+			standard: "gip69v",
+			wantErr:  true,
+		},
+		{
+			name:     "Non-numeric version",
+			standard: "gip69vx",
+			wantErr:  true,
+		},
+		{
+			name:     "Leading zero",
+			standard: "gip69v02",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, version, err := zkcertificate.ParseStandardVersion(tt.standard)
+			if tt.wantErr {
+				require.ErrorIs(t, err, zkcertificate.ErrNoVersionSuffix)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantName, name)
+			require.Equal(t, tt.wantVersion, version)
+		})
+	}
+}
+
+func TestStandard_NamespaceAndName(t *testing.T) {
+	tests := []struct {
+		standard      zkcertificate.Standard
+		wantNamespace string
+		wantName      string
+	}{
+		{standard: "galactica/kyc", wantNamespace: "galactica", wantName: "kyc"},
+		{standard: "eip-712", wantNamespace: "eip", wantName: "712"},
+		{standard: "org/kyc-v2", wantNamespace: "org", wantName: "kyc-v2"},
+		{standard: "/kyc", wantNamespace: "", wantName: "kyc"},
+		{standard: zkcertificate.StandardKYC, wantNamespace: "", wantName: "gip1"},
+		{standard: "", wantNamespace: "", wantName: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.standard), func(t *testing.T) {
+			require.Equal(t, tt.wantNamespace, tt.standard.Namespace())
+			require.Equal(t, tt.wantName, tt.standard.Name())
+		})
+	}
+}
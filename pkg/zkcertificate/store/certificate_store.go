@@ -0,0 +1,188 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+// Package store persists issued zero-knowledge certificates in a SQLite database.
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the sqlite database driver
+
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+const certificateStoreSchema = `
+CREATE TABLE IF NOT EXISTS certificates (
+	did             TEXT PRIMARY KEY,
+	leaf_hash       TEXT NOT NULL,
+	expiration_date INTEGER NOT NULL,
+	revoked         INTEGER NOT NULL DEFAULT 0,
+	certificate     BLOB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS certificates_expiration_date ON certificates (expiration_date);
+`
+
+// CertificateStore persists issued certificates in a SQLite database.
+type CertificateStore[T zkcertificate.Content] struct {
+	db *sql.DB
+}
+
+// NewCertificateStore opens the SQLite database at the given data source name
+// and creates the certificates table if it does not exist.
+func NewCertificateStore[T zkcertificate.Content](dataSourceName string) (*CertificateStore[T], error) {
+	db, err := sql.Open("sqlite", dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+
+	// SQLite does not support concurrent writers.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(certificateStoreSchema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("create schema: %w", err)
+	}
+
+	return &CertificateStore[T]{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *CertificateStore[T]) Close() error {
+	return s.db.Close()
+}
+
+// Store saves the certificate, replacing a previously stored certificate with the same DID.
+// The revocation status of a replaced certificate is preserved.
+func (s *CertificateStore[T]) Store(cert *zkcertificate.IssuedCertificate[T]) error {
+	if cert.DID == "" {
+		return fmt.Errorf("certificate has no did")
+	}
+
+	data, err := json.Marshal(cert)
+	if err != nil {
+		return fmt.Errorf("encode certificate: %w", err)
+	}
+
+	if _, err := s.db.Exec(
+		`INSERT INTO certificates (did, leaf_hash, expiration_date, certificate) VALUES (?, ?, ?, ?)
+		ON CONFLICT (did) DO UPDATE SET
+			leaf_hash = excluded.leaf_hash,
+			expiration_date = excluded.expiration_date,
+			certificate = excluded.certificate`,
+		cert.DID,
+		cert.LeafHash.String(),
+		cert.ExpirationDate.Unix(),
+		data,
+	); err != nil {
+		return fmt.Errorf("insert certificate: %w", err)
+	}
+
+	return nil
+}
+
+// Load returns the certificate with the given DID
+// or zkcertificate.ErrCertificateNotFound if it is not stored.
+func (s *CertificateStore[T]) Load(did string) (*zkcertificate.IssuedCertificate[T], error) {
+	var data []byte
+	if err := s.db.QueryRow(`SELECT certificate FROM certificates WHERE did = ?`, did).Scan(&data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, zkcertificate.ErrCertificateNotFound
+		}
+
+		return nil, fmt.Errorf("select certificate: %w", err)
+	}
+
+	return decodeStoredCertificate[T](data)
+}
+
+// ListExpired returns all certificates expiring before the given time, ordered by expiration date.
+func (s *CertificateStore[T]) ListExpired(before time.Time) ([]*zkcertificate.IssuedCertificate[T], error) {
+	rows, err := s.db.Query(
+		`SELECT certificate FROM certificates WHERE expiration_date < ? ORDER BY expiration_date, did`,
+		before.Unix(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select certificates: %w", err)
+	}
+	defer rows.Close()
+
+	var certificates []*zkcertificate.IssuedCertificate[T]
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("scan certificate: %w", err)
+		}
+
+		certificate, err := decodeStoredCertificate[T](data)
+		if err != nil {
+			return nil, err
+		}
+
+		certificates = append(certificates, certificate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate certificates: %w", err)
+	}
+
+	return certificates, nil
+}
+
+// MarkRevoked marks the certificate with the given DID as revoked.
+func (s *CertificateStore[T]) MarkRevoked(did string) error {
+	res, err := s.db.Exec(`UPDATE certificates SET revoked = 1 WHERE did = ?`, did)
+	if err != nil {
+		return fmt.Errorf("update certificate: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get affected rows: %w", err)
+	}
+
+	if affected == 0 {
+		return zkcertificate.ErrCertificateNotFound
+	}
+
+	return nil
+}
+
+// IsRevoked reports whether the certificate with the given DID was marked as revoked.
+func (s *CertificateStore[T]) IsRevoked(did string) (bool, error) {
+	var revoked bool
+	if err := s.db.QueryRow(`SELECT revoked FROM certificates WHERE did = ?`, did).Scan(&revoked); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, zkcertificate.ErrCertificateNotFound
+		}
+
+		return false, fmt.Errorf("select certificate: %w", err)
+	}
+
+	return revoked, nil
+}
+
+func decodeStoredCertificate[T zkcertificate.Content](data []byte) (*zkcertificate.IssuedCertificate[T], error) {
+	var certificate zkcertificate.IssuedCertificate[T]
+	if err := json.Unmarshal(data, &certificate); err != nil {
+		return nil, fmt.Errorf("decode certificate: %w", err)
+	}
+
+	return &certificate, nil
+}
@@ -0,0 +1,132 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package store_test
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate/store"
+)
+
+func TestCertificateStore(t *testing.T) {
+	certificates := makeCertificateStore(t)
+
+	issued := makeIssuedCertificate(t)
+	require.NoError(t, certificates.Store(issued))
+
+	loaded, err := certificates.Load(issued.DID)
+	require.NoError(t, err)
+	require.Equal(t, issued.LeafHash, loaded.LeafHash)
+	require.Equal(t, issued.ContentHash, loaded.ContentHash)
+	require.Equal(t, issued.Registration, loaded.Registration)
+	require.Equal(t, issued.ExpirationDate.Unix(), loaded.ExpirationDate.Unix())
+	require.Equal(t, issued.MerkleProof.LeafIndex, loaded.MerkleProof.LeafIndex)
+
+	revoked, err := certificates.IsRevoked(issued.DID)
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	require.NoError(t, certificates.MarkRevoked(issued.DID))
+	require.NoError(t, certificates.Store(issued))
+
+	revoked, err = certificates.IsRevoked(issued.DID)
+	require.NoError(t, err)
+	require.True(t, revoked)
+}
+
+func TestCertificateStore_notFound(t *testing.T) {
+	certificates := makeCertificateStore(t)
+
+	_, err := certificates.Load("did:gip2:1")
+	require.ErrorIs(t, err, zkcertificate.ErrCertificateNotFound)
+
+	require.ErrorIs(t, certificates.MarkRevoked("did:gip2:1"), zkcertificate.ErrCertificateNotFound)
+}
+
+func TestCertificateStore_ListExpired(t *testing.T) {
+	certificates := makeCertificateStore(t)
+
+	issued := makeIssuedCertificate(t)
+	require.NoError(t, certificates.Store(issued))
+
+	expired, err := certificates.ListExpired(time.Unix(1700000000, 0))
+	require.NoError(t, err)
+	require.Empty(t, expired)
+
+	expired, err = certificates.ListExpired(time.Unix(1700000001, 0))
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	require.Equal(t, issued.DID, expired[0].DID)
+}
+
+func makeCertificateStore(t *testing.T) *store.CertificateStore[zkcertificate.SimpleJSONContent] {
+	t.Helper()
+
+	certificates, err := store.NewCertificateStore[zkcertificate.SimpleJSONContent](
+		filepath.Join(t.TempDir(), "certificates.db"),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, certificates.Close())
+	})
+
+	return certificates
+}
+
+func makeIssuedCertificate(t *testing.T) *zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent] {
+	t.Helper()
+
+	privateKey := babyjub.NewRandPrivKey()
+	holderCommitment := zkcertificate.HashFromBigInt(big.NewInt(7))
+
+	content, err := zkcertificate.SimpleJSON{"name": "John Doe"}.FFEncode()
+	require.NoError(t, err)
+
+	contentHash, err := content.Hash()
+	require.NoError(t, err)
+
+	signature, err := zkcertificate.SignCertificate(privateKey, contentHash, holderCommitment)
+	require.NoError(t, err)
+
+	certificate, err := zkcertificate.New(holderCommitment, content, privateKey.Public(), signature, 1, time.Unix(1700000000, 0))
+	require.NoError(t, err)
+
+	tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+	require.NoError(t, tree.SetLeaf(1, merkle.TreeNode{Value: uint256.MustFromBig(certificate.LeafHash.BigInt())}))
+
+	proof, err := tree.GetProof(1)
+	require.NoError(t, err)
+
+	return &zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent]{
+		Certificate: *certificate,
+		Registration: zkcertificate.RegistrationDetails{
+			Revocable: true,
+			LeafIndex: 1,
+		},
+		MerkleProof: proof,
+	}
+}
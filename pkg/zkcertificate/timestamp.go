@@ -22,9 +22,15 @@ import (
 )
 
 // Timestamp represents a type that holds a time.Time value that is serialized as Unix timestamp.
+// Timestamps are expected to be in UTC, as used in ZK circuit inputs and Solidity events.
 type Timestamp time.Time
 
-// Unix returns the Unix timestamp of the Timestamp.
+// TimestampFromUnix creates a Timestamp in UTC from the given Unix timestamp.
+func TimestampFromUnix(ts int64) Timestamp {
+	return Timestamp(time.Unix(ts, 0).UTC())
+}
+
+// Unix returns the Unix timestamp of the Timestamp. It is an alias for time.Time.Unix.
 func (t Timestamp) Unix() int64 {
 	return time.Time(t).Unix()
 }
@@ -47,6 +53,6 @@ func (t *Timestamp) UnmarshalJSON(bytes []byte) error {
 		return err
 	}
 
-	*t = Timestamp(time.Unix(unix, 0))
+	*t = TimestampFromUnix(unix)
 	return nil
 }
@@ -25,6 +25,14 @@ import (
 	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
 )
 
+func TestTimestampFromUnix(t *testing.T) {
+	timestamp := zkcertificate.TimestampFromUnix(1700000000)
+
+	require.Equal(t, int64(1700000000), timestamp.Unix())
+	require.Equal(t, time.UTC, time.Time(timestamp).Location())
+	require.Equal(t, "2023-11-14T22:13:20Z", time.Time(timestamp).Format(time.RFC3339))
+}
+
 func TestTimestamp_Unix(t *testing.T) {
 	now := time.Now()
 	timestamp := zkcertificate.Timestamp(now)
@@ -33,7 +41,7 @@ func TestTimestamp_Unix(t *testing.T) {
 }
 
 func TestTimestamp_JSON(t *testing.T) {
-	unix := time.Unix(1700000000, 0)
+	unix := time.Unix(1700000000, 0).UTC()
 	timestamp := zkcertificate.Timestamp(unix)
 
 	data, err := json.Marshal(timestamp)
@@ -45,6 +53,13 @@ func TestTimestamp_JSON(t *testing.T) {
 	require.Equal(t, timestamp, deserialized)
 }
 
+func TestTimestamp_UnmarshalJSON_UTC(t *testing.T) {
+	var deserialized zkcertificate.Timestamp
+	require.NoError(t, json.Unmarshal([]byte(`1700000000`), &deserialized))
+	require.Equal(t, time.UTC, time.Time(deserialized).Location())
+	require.Equal(t, "2023-11-14T22:13:20Z", time.Time(deserialized).Format(time.RFC3339))
+}
+
 func TestTimestamp_JSON_null(t *testing.T) {
 	var deserialized zkcertificate.Timestamp
 	require.NoError(t, json.Unmarshal([]byte(`null`), &deserialized))
@@ -0,0 +1,155 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/iden3/go-iden3-crypto/utils"
+)
+
+// CircuitDescription describes the input signals of a ZK circuit.
+type CircuitDescription struct {
+	Name    string   `json:"name"`
+	Signals []string `json:"signals"`
+}
+
+// WitnessGenerator produces witness inputs for the certificate membership circuit
+// in the format expected by snarkjs calculateWitness.
+type WitnessGenerator[T Content] struct {
+	circuit CircuitDescription
+}
+
+// NewWitnessGenerator creates a witness generator for the circuit described in the given JSON file.
+func NewWitnessGenerator[T Content](circuitDescriptionPath string) (*WitnessGenerator[T], error) {
+	data, err := os.ReadFile(circuitDescriptionPath)
+	if err != nil {
+		return nil, fmt.Errorf("read circuit description: %w", err)
+	}
+
+	var circuit CircuitDescription
+	if err := json.Unmarshal(data, &circuit); err != nil {
+		return nil, fmt.Errorf("decode circuit description: %w", err)
+	}
+
+	if len(circuit.Signals) == 0 {
+		return nil, fmt.Errorf("circuit description has no signals")
+	}
+
+	return &WitnessGenerator[T]{circuit: circuit}, nil
+}
+
+// Generate validates the issued certificate against the holder secret and returns the values
+// of all signals listed in the circuit description. All values are decimal strings within the field.
+func (g *WitnessGenerator[T]) Generate(cert *IssuedCertificate[T], holderSecret *big.Int) (map[string]interface{}, error) {
+	if err := validateWitnessCertificate(cert, holderSecret); err != nil {
+		return nil, fmt.Errorf("validate certificate: %w", err)
+	}
+
+	inputs, err := cert.ToZKProofInputs()
+	if err != nil {
+		return nil, fmt.Errorf("collect proof inputs: %w", err)
+	}
+
+	inputs["holderSecret"] = holderSecret.String()
+
+	witness := make(map[string]interface{}, len(g.circuit.Signals))
+	for _, signal := range g.circuit.Signals {
+		value, ok := inputs[signal]
+		if !ok {
+			return nil, fmt.Errorf("unknown signal %q", signal)
+		}
+
+		if err := validateWitnessValue(value); err != nil {
+			return nil, fmt.Errorf("signal %q: %w", signal, err)
+		}
+
+		witness[signal] = value
+	}
+
+	return witness, nil
+}
+
+func validateWitnessCertificate[T Content](cert *IssuedCertificate[T], holderSecret *big.Int) error {
+	holderCommitment, err := GenerateHolderCommitment(holderSecret)
+	if err != nil {
+		return fmt.Errorf("generate holder commitment: %w", err)
+	}
+
+	if holderCommitment.Cmp(cert.HolderCommitment) != 0 {
+		return fmt.Errorf("holder secret does not match holder commitment")
+	}
+
+	contentHash, err := cert.Content.Hash()
+	if err != nil {
+		return fmt.Errorf("hash certificate content: %w", err)
+	}
+
+	if contentHash.Cmp(cert.ContentHash) != 0 {
+		return fmt.Errorf("content hash mismatch")
+	}
+
+	signatureValid, err := VerifySignature(
+		&cert.Provider.PublicKey,
+		cert.ContentHash,
+		cert.HolderCommitment,
+		&cert.Provider.Signature,
+	)
+	if err != nil {
+		return fmt.Errorf("verify signature: %w", err)
+	}
+	if !signatureValid {
+		return fmt.Errorf("invalid signature")
+	}
+
+	leafHash, err := cert.computeLeafHash()
+	if err != nil {
+		return fmt.Errorf("compute leaf hash: %w", err)
+	}
+
+	if leafHash.Cmp(cert.LeafHash) != 0 {
+		return fmt.Errorf("leaf hash mismatch")
+	}
+
+	return nil
+}
+
+func validateWitnessValue(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		n, ok := new(big.Int).SetString(v, 10)
+		if !ok {
+			return fmt.Errorf("invalid decimal value %q", v)
+		}
+
+		if n.Sign() < 0 || !utils.CheckBigIntInField(n) {
+			return fmt.Errorf("value %s is not in the field", v)
+		}
+	case []string:
+		for i, element := range v {
+			if err := validateWitnessValue(element); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported value type %T", value)
+	}
+
+	return nil
+}
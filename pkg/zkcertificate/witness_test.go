@@ -0,0 +1,153 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestWitnessGenerator_Generate(t *testing.T) {
+	holderSecret := big.NewInt(11)
+	issued := makeIssuedCertificateForSecret(t, holderSecret)
+
+	generator, err := zkcertificate.NewWitnessGenerator[zkcertificate.SimpleJSONContent](writeCircuitDescription(t,
+		`{"name":"zkCertificate","signals":["holderSecret","leafHash","randomSalt","pathElements","leafIndex"]}`,
+	))
+	require.NoError(t, err)
+
+	witness, err := generator.Generate(issued, holderSecret)
+	require.NoError(t, err)
+	require.Len(t, witness, 5)
+	require.Equal(t, "11", witness["holderSecret"])
+	require.Equal(t, issued.LeafHash.String(), witness["leafHash"])
+	require.Equal(t, "1", witness["randomSalt"])
+	require.Len(t, witness["pathElements"], 2)
+	require.Equal(t, "0", witness["leafIndex"])
+}
+
+func TestWitnessGenerator_Generate_invalid(t *testing.T) {
+	holderSecret := big.NewInt(11)
+
+	tests := []struct {
+		name         string
+		circuit      string
+		holderSecret *big.Int
+		modify       func(issued *zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent])
+	}{
+		{
+			name:         "wrong holder secret",
+			circuit:      `{"signals":["leafHash"]}`,
+			holderSecret: big.NewInt(54321),
+		},
+		{
+			name:         "holder secret out of field",
+			circuit:      `{"signals":["leafHash"]}`,
+			holderSecret: new(big.Int).Lsh(big.NewInt(1), 256),
+		},
+		{
+			name:         "unknown signal",
+			circuit:      `{"signals":["nonExistent"]}`,
+			holderSecret: holderSecret,
+		},
+		{
+			name:         "tampered leaf hash",
+			circuit:      `{"signals":["leafHash"]}`,
+			holderSecret: holderSecret,
+			modify: func(issued *zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent]) {
+				issued.LeafHash = zkcertificate.HashFromBigInt(big.NewInt(1))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issued := makeIssuedCertificateForSecret(t, holderSecret)
+			if tt.modify != nil {
+				tt.modify(issued)
+			}
+
+			generator, err := zkcertificate.NewWitnessGenerator[zkcertificate.SimpleJSONContent](
+				writeCircuitDescription(t, tt.circuit),
+			)
+			require.NoError(t, err)
+
+			_, err = generator.Generate(issued, tt.holderSecret)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestNewWitnessGenerator_noSignals(t *testing.T) {
+	_, err := zkcertificate.NewWitnessGenerator[zkcertificate.SimpleJSONContent](writeCircuitDescription(t, `{}`))
+	require.Error(t, err)
+}
+
+func writeCircuitDescription(t *testing.T, description string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "circuit.json")
+	require.NoError(t, os.WriteFile(path, []byte(description), 0o600))
+
+	return path
+}
+
+// makeIssuedCertificateForSecret issues a certificate for the given holder secret.
+func makeIssuedCertificateForSecret(
+	t *testing.T,
+	holderSecret *big.Int,
+) *zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent] {
+	t.Helper()
+
+	holderCommitment, err := zkcertificate.GenerateHolderCommitment(holderSecret)
+	require.NoError(t, err)
+
+	privateKey := babyjub.NewRandPrivKey()
+
+	content, err := zkcertificate.SimpleJSON{"name": "John Doe"}.FFEncode()
+	require.NoError(t, err)
+
+	contentHash, err := content.Hash()
+	require.NoError(t, err)
+
+	signature, err := zkcertificate.SignCertificate(privateKey, contentHash, holderCommitment)
+	require.NoError(t, err)
+
+	certificate, err := zkcertificate.New(holderCommitment, content, privateKey.Public(), signature, 1, time.Unix(1700000000, 0))
+	require.NoError(t, err)
+
+	tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+	require.NoError(t, tree.SetLeaf(0, merkle.TreeNode{Value: uint256.MustFromBig(certificate.LeafHash.BigInt())}))
+
+	proof, err := tree.GetProof(0)
+	require.NoError(t, err)
+
+	return &zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent]{
+		Certificate: *certificate,
+		MerkleProof: proof,
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ToZKProofInputs converts the issued certificate and its Merkle proof into the snarkjs input format
+// using the signal names of the Galactica ZK circuits. All field elements are encoded as decimal strings.
+func (c *IssuedCertificate[T]) ToZKProofInputs() (map[string]interface{}, error) {
+	if c.Provider.PublicKey.X == nil || c.Provider.PublicKey.Y == nil {
+		return nil, fmt.Errorf("incomplete provider public key")
+	}
+
+	if c.Provider.Signature.R8 == nil || c.Provider.Signature.S == nil {
+		return nil, fmt.Errorf("incomplete provider signature")
+	}
+
+	if c.MerkleProof.LeafIndex != c.Registration.LeafIndex {
+		return nil, fmt.Errorf(
+			"merkle proof leaf index %d does not match registration leaf index %d",
+			c.MerkleProof.LeafIndex,
+			c.Registration.LeafIndex,
+		)
+	}
+
+	pathElements := make([]string, len(c.MerkleProof.Path))
+	for i, node := range c.MerkleProof.Path {
+		if node.Value == nil {
+			return nil, fmt.Errorf("merkle proof node %d is empty", i)
+		}
+
+		pathElements[i] = node.Value.Dec()
+	}
+
+	return map[string]interface{}{
+		"contentHash":      c.ContentHash.String(),
+		"holderCommitment": c.HolderCommitment.String(),
+		"leafHash":         c.LeafHash.String(),
+		"randomSalt":       strconv.FormatInt(c.RandomSalt, 10),
+		"expirationDate":   strconv.FormatInt(c.ExpirationDate.Unix(), 10),
+		"providerAx":       c.Provider.PublicKey.X.String(),
+		"providerAy":       c.Provider.PublicKey.Y.String(),
+		"providerS":        c.Provider.Signature.S.String(),
+		"providerR8x":      c.Provider.Signature.R8.X.String(),
+		"providerR8y":      c.Provider.Signature.R8.Y.String(),
+		"pathElements":     pathElements,
+		"leafIndex":        strconv.Itoa(c.MerkleProof.LeafIndex),
+	}, nil
+}
@@ -0,0 +1,86 @@
+// Copyright © 2024 Galactica Network
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package zkcertificate_test
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/require"
+
+	"github.com/galactica-corp/guardians-sdk/pkg/merkle"
+	"github.com/galactica-corp/guardians-sdk/pkg/zkcertificate"
+)
+
+func TestIssuedCertificate_ToZKProofInputs(t *testing.T) {
+	issued := makeIssuedCertificate(t)
+
+	inputs, err := issued.ToZKProofInputs()
+	require.NoError(t, err)
+
+	require.Equal(t, issued.ContentHash.String(), inputs["contentHash"])
+	require.Equal(t, issued.LeafHash.String(), inputs["leafHash"])
+	require.Equal(t, "7", inputs["holderCommitment"])
+	require.Equal(t, "1", inputs["randomSalt"])
+	require.Equal(t, "1700000000", inputs["expirationDate"])
+	require.Equal(t, issued.Provider.PublicKey.X.String(), inputs["providerAx"])
+	require.Equal(t, issued.Provider.Signature.S.String(), inputs["providerS"])
+	require.Equal(t, "1", inputs["leafIndex"])
+	require.Len(t, inputs["pathElements"], 2)
+}
+
+func TestIssuedCertificate_ToZKProofInputs_leafIndexMismatch(t *testing.T) {
+	issued := makeIssuedCertificate(t)
+	issued.Registration.LeafIndex = 2
+
+	_, err := issued.ToZKProofInputs()
+	require.Error(t, err)
+}
+
+func makeIssuedCertificate(t *testing.T) *zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent] {
+	t.Helper()
+
+	issued, _ := makeIssuedCertificateWithKey(t)
+
+	return issued
+}
+
+func makeIssuedCertificateWithKey(
+	t *testing.T,
+) (*zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent], babyjub.PrivateKey) {
+	t.Helper()
+
+	certificate, privateKey := makeCertificate(t)
+
+	tree, err := merkle.NewEmptyTree(2, merkle.EmptyLeafValue)
+	require.NoError(t, err)
+
+	leafIndex := 1
+	require.NoError(t, tree.SetLeaf(leafIndex, merkle.TreeNode{Value: uint256.MustFromBig(certificate.LeafHash.BigInt())}))
+
+	proof, err := tree.GetProof(leafIndex)
+	require.NoError(t, err)
+
+	return &zkcertificate.IssuedCertificate[zkcertificate.SimpleJSONContent]{
+		Certificate: *certificate,
+		Registration: zkcertificate.RegistrationDetails{
+			Revocable: true,
+			LeafIndex: leafIndex,
+		},
+		MerkleProof: proof,
+	}, privateKey
+}